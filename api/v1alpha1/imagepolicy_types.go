@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Enforcement modes accepted in spec.mode of image policies.
+const (
+	// ImagePolicyModeEnforce rejects pods whose images fail verification and
+	// deletes running ones once the failure persists.
+	ImagePolicyModeEnforce = "Enforce"
+	// ImagePolicyModeWarn admits failing images with a warning and reports
+	// failing pods through Events and notifications.
+	ImagePolicyModeWarn = "Warn"
+	// ImagePolicyModeAudit only records images that fail verification.
+	ImagePolicyModeAudit = "Audit"
+)
+
+// Actions accepted in spec.action of image policies.
+const (
+	// ImagePolicyActionDelete deletes running pods whose images keep failing verification.
+	ImagePolicyActionDelete = "Delete"
+	// ImagePolicyActionQuarantine isolates such pods from the network and
+	// scales their workload to zero until the image verifies again.
+	ImagePolicyActionQuarantine = "Quarantine"
+)
+
+// ImagePolicyKey is a cosign public key trusted by an image policy.
+type ImagePolicyKey struct {
+	// Name identifies the key in verification results.
+	Name string `json:"name"`
+	// PublicKey is the PEM-encoded cosign public key.
+	PublicKey string `json:"publicKey"`
+	// NotAfter stops trusting the key after this time, e.g. once it was rotated out.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// ImagePolicyIdentity is a certificate identity trusted for keyless signatures.
+// Issuer and subject are each matched exactly or by their regular expression.
+type ImagePolicyIdentity struct {
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+	// +optional
+	IssuerRegExp string `json:"issuerRegExp,omitempty"`
+	// +optional
+	Subject string `json:"subject,omitempty"`
+	// +optional
+	SubjectRegExp string `json:"subjectRegExp,omitempty"`
+}
+
+// ImagePolicyAttestation requires a signed in-toto attestation on the image.
+type ImagePolicyAttestation struct {
+	// PredicateType is a predicate URI or a cosign short name such as
+	// slsaprovenance, slsaprovenance1, cyclonedx or spdxjson.
+	PredicateType string `json:"predicateType"`
+	// BuilderID is a glob the SLSA provenance builder ID must match.
+	// +optional
+	BuilderID string `json:"builderID,omitempty"`
+	// SourceRepo is a glob the SLSA provenance source repository must match,
+	// e.g. github.com/shieldx-bot/*.
+	// +optional
+	SourceRepo string `json:"sourceRepo,omitempty"`
+}
+
+// ImagePolicySpec declares how the images it matches must be signed.
+type ImagePolicySpec struct {
+	// Images are glob patterns of the image references the policy applies to,
+	// e.g. ghcr.io/shieldx-bot/*. "*" matches any characters, including "/".
+	// When several policies match an image, the one with the longest matching
+	// pattern wins.
+	// +kubebuilder:validation:MinItems=1
+	Images []string `json:"images"`
+
+	// Exemptions are glob patterns of matched images admitted without verification.
+	// +optional
+	Exemptions []string `json:"exemptions,omitempty"`
+
+	// Keys are the cosign public keys signatures may be made with.
+	// +optional
+	Keys []ImagePolicyKey `json:"keys,omitempty"`
+
+	// Identities are the certificate identities trusted for keyless signatures.
+	// +optional
+	Identities []ImagePolicyIdentity `json:"identities,omitempty"`
+
+	// Attestations must all be present and signed by the image signer.
+	// +optional
+	Attestations []ImagePolicyAttestation `json:"attestations,omitempty"`
+
+	// Mode decides whether failing images are blocked, reported or only recorded.
+	// +kubebuilder:validation:Enum=Enforce;Warn;Audit
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Action is taken on running pods that keep failing under an Enforce policy.
+	// Quarantine avoids the churn of deleting pods their workload recreates.
+	// +kubebuilder:validation:Enum=Delete;Quarantine
+	// +kubebuilder:default=Delete
+	// +optional
+	Action string `json:"action,omitempty"`
+
+	// IgnoreTlog skips transparency log verification when the Rekor public
+	// keys cannot be loaded.
+	// +optional
+	IgnoreTlog bool `json:"ignoreTlog,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// TenantImagePolicy is the Schema for the tenantimagepolicies API.
+// It applies to images of workloads in its own namespace and takes
+// precedence over ClusterImagePolicies.
+type TenantImagePolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the images covered and how they must be signed
+	// +required
+	Spec ImagePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenantImagePolicyList contains a list of TenantImagePolicy
+type TenantImagePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []TenantImagePolicy `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterImagePolicy is the Schema for the clusterimagepolicies API.
+// It applies to images in every namespace without a matching TenantImagePolicy.
+type ClusterImagePolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the images covered and how they must be signed
+	// +required
+	Spec ImagePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterImagePolicyList contains a list of ClusterImagePolicy
+type ClusterImagePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClusterImagePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantImagePolicy{}, &TenantImagePolicyList{})
+	SchemeBuilder.Register(&ClusterImagePolicy{}, &ClusterImagePolicyList{})
+}
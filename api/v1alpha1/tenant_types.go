@@ -41,17 +41,65 @@ type ResourceQuota struct {
 	Pods string `json:"pods,omitempty"`
 }
 
-// NetworkPolicyPeer is a simplified selector for a peer Pod.
+// NetworkPolicyPeer is a simplified selector for a traffic peer.
 // NOTE: This is NOT the full Kubernetes NetworkPolicyPeer model.
-// It only supports selecting Pods by labels ("pod"), implicitly within the same namespace.
+// With only "pod" set, Pods are selected within the tenant namespace.
+// "namespace" and "tenant" widen the selection to other namespaces, and
+// "ipBlock" selects a CIDR and cannot be combined with the other fields.
 type NetworkPolicyPeer struct {
 	Pod map[string]string `json:"pod,omitempty"`
+
+	// Namespace selects namespaces by label.
+	// +optional
+	Namespace map[string]string `json:"namespace,omitempty"`
+
+	// Tenant selects the namespace of another Tenant by name. It resolves to the
+	// Tenant that owns namespace tenant-<name>, whatever namespace it lives in.
+	// Egress to a tenant is only allowed when that tenant has an ingress rule from this one.
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// IPBlock selects a range of IP addresses.
+	// +optional
+	IPBlock *IPBlock `json:"ipBlock,omitempty"`
+}
+
+// IPBlock describes a CIDR with optional exceptions.
+type IPBlock struct {
+	// CIDR is an IP range such as "10.0.0.0/8".
+	CIDR string `json:"cidr"`
+	// Except lists CIDRs inside CIDR that are not selected.
+	// +optional
+	Except []string `json:"except,omitempty"`
+}
+
+// NetworkPolicyPort restricts a rule to a protocol and port or port range.
+type NetworkPolicyPort struct {
+	// Protocol defaults to TCP.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// EndPort makes the rule cover the range port..endPort.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	EndPort int32 `json:"endPort,omitempty"`
 }
+
 type NetworkPolicyEgressRule struct {
 	To NetworkPolicyPeer `json:"to,omitempty"`
+	// Ports limits the rule; empty means every port.
+	// +optional
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
 }
 type NetworkPolicyIngressRule struct {
 	From NetworkPolicyPeer `json:"from,omitempty"`
+	// Ports limits the rule; empty means every port.
+	// +optional
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
 }
 
 type NetworkPolicy struct {
@@ -66,20 +114,127 @@ type NetworkPolicy struct {
 
 // TenantSpec defines the desired state of Tenant
 type TenantSpec struct {
-	// Owners is a list of owner identities (email/OIDC subject/group).
+	// Owners is a list of owner identities (email/OIDC subject/group), bound to the admin ClusterRole.
+	// Plain entries are users; "group:<name>" is a group and "sa:[<namespace>/]<name>"
+	// a ServiceAccount, defaulting to the tenant namespace.
 	// +kubebuilder:validation:MinItems=1
 	Owners []string `json:"owners"`
 
-	Tier          string `json:"tier"`
-	Isolation     string `json:"isolation"`
+	// Members are bound to the edit ClusterRole, using the same format as owners.
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// Viewers are bound to the view ClusterRole, using the same format as owners.
+	// +optional
+	Viewers []string `json:"viewers,omitempty"`
+
+	// Tier is the name of the TenantTier supplying quota, limit and priority defaults.
+	// The defaulting webhook fills in the TenantTier annotated platform.shieldx.io/default-tier.
+	// +optional
+	Tier string `json:"tier,omitempty"`
+	// Isolation defaults to "namespace".
+	// +optional
+	Isolation     string `json:"isolation,omitempty"`
 	NetworkPolicy `json:"networkPolicy,omitempty"`
+	// ResourceQuota limits the tenant namespace. The defaulting webhook fills
+	// the fields left empty from the tier, so the stored Tenant is explicit.
+	// Moving to another tier replaces the values inherited from the old one,
+	// but later edits to a TenantTier only reach Tenants created or moved after.
 	ResourceQuota `json:"resourceQuota,omitempty"`
+
+	// DeletionPolicy decides what deleting the Tenant does: Delete tears the
+	// namespace down, Retain keeps it for later re-adoption by a Tenant with
+	// the same name and namespace (or one named in the adopt annotation) and
+	// Protect rejects the deletion.
+	// +kubebuilder:validation:Enum=Protect;Retain;Delete
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// ScaleDownOnDelete scales Deployments and StatefulSets to zero before
+	// the tenant namespace is torn down, so workloads stop gracefully.
+	// +optional
+	ScaleDownOnDelete bool `json:"scaleDownOnDelete,omitempty"`
+
+	// EnforceImageSignatures labels the tenant namespace
+	// security.shieldx.io/policy=enforce, opting it into the image signature webhooks.
+	// +optional
+	EnforceImageSignatures bool `json:"enforceImageSignatures,omitempty"`
 }
 
+// IsolationNamespace gives the tenant a dedicated namespace; it is the only supported isolation mode.
+const IsolationNamespace = "namespace"
+
+// Deletion policies accepted in spec.deletionPolicy.
+const (
+	DeletionPolicyProtect = "Protect"
+	DeletionPolicyRetain  = "Retain"
+	DeletionPolicyDelete  = "Delete"
+)
+
+// TenantNameLabel and TenantNamespaceLabel name the Tenant that owns a tenant
+// namespace and the objects the controller manages in it. A Tenant never
+// adopts a namespace these labels assign to another Tenant.
+const (
+	TenantNameLabel      = "tenant"
+	TenantNamespaceLabel = "platform.shieldx.io/tenant-namespace"
+)
+
+// RetainedLabel marks a namespace kept by deletionPolicy Retain.
+const RetainedLabel = "platform.shieldx.io/retained"
+
+// AdoptAnnotation set to "<namespace>/<name>" on a retained or unmanaged
+// namespace lets that Tenant adopt it; a retained namespace is otherwise only
+// adopted again by a Tenant with the same name and namespace.
+const AdoptAnnotation = "platform.shieldx.io/adopt"
+
+// ConfirmDeleteAnnotation must be set to the Tenant name before deleting a
+// Tenant whose namespace still runs pods.
+const ConfirmDeleteAnnotation = "platform.shieldx.io/confirm-delete"
+
+// ImagePolicyLabel set to ImagePolicyEnforce on a namespace opts it into
+// image signature enforcement.
+const (
+	ImagePolicyLabel   = "security.shieldx.io/policy"
+	ImagePolicyEnforce = "enforce"
+)
+
+// OriginalImagesAnnotation records, as a JSON object keyed by container name,
+// the image references a pod declared before they were pinned to digests.
+const OriginalImagesAnnotation = "security.shieldx.io/original-images"
+
+// Tenant phases reported in status.phase.
+const (
+	TenantPhasePending = "Pending"
+	TenantPhaseReady   = "Ready"
+	TenantPhaseError   = "Error"
+	// TenantPhaseTerminating is set while the finalizer tears the tenant down.
+	TenantPhaseTerminating = "Terminating"
+)
+
+// Condition types reported in status.conditions.
+const (
+	// TenantConditionTierResolved reports whether spec.tier names an existing TenantTier.
+	TenantConditionTierResolved = "TierResolved"
+	// TenantConditionNamespaceReady reports whether the tenant namespace exists and is active.
+	TenantConditionNamespaceReady = "NamespaceReady"
+	// TenantConditionQuotaReady reports whether the tenant ResourceQuota and LimitRange match the spec.
+	TenantConditionQuotaReady = "QuotaReady"
+	// TenantConditionNetworkPolicyReady reports whether the tenant NetworkPolicies are applied.
+	TenantConditionNetworkPolicyReady = "NetworkPolicyReady"
+	// TenantConditionRBACReady reports whether the tenant RoleBindings match owners, members and viewers.
+	TenantConditionRBACReady = "RBACReady"
+	// TenantConditionImagePolicyEnforced reports whether the namespace is labeled for image
+	// signature enforcement and an image policy applies in it.
+	TenantConditionImagePolicyEnforced = "ImagePolicyEnforced"
+	// TenantConditionDeleting reports the current teardown step once the Tenant is being deleted.
+	TenantConditionDeleting = "Deleting"
+)
+
 // TenantStatus defines the observed state of Tenant.
 type TenantStatus struct {
 	// Phase is a simple, high-level summary of the tenant state.
-	// +kubebuilder:validation:Enum=Pending;Ready;Error
+	// +kubebuilder:validation:Enum=Pending;Ready;Error;Terminating
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
@@ -97,6 +252,10 @@ type TenantStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Tier",type=string,JSONPath=`.spec.tier`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.status.namespace`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Tenant is the Schema for the tenants API
 type Tenant struct {
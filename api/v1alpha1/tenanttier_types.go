@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultTierAnnotation set to "true" on a TenantTier makes it the tier of Tenants that do not name one.
+const DefaultTierAnnotation = "platform.shieldx.io/default-tier"
+
+// TierLabel carries the tier name on Tenants and on tenant namespaces.
+const TierLabel = "platform.shieldx.io/tier"
+
+// PriorityClassAnnotation publishes the tier PriorityClass on tenant namespaces;
+// the pod priority webhook applies it to pods that do not set one.
+const PriorityClassAnnotation = "platform.shieldx.io/priority-class"
+
+// TierLimitRange holds the per-container defaults and maximums applied as a
+// LimitRange in every tenant namespace of a tier.
+type TierLimitRange struct {
+	// DefaultCPU is the CPU limit given to containers that do not set one.
+	// +optional
+	DefaultCPU string `json:"defaultCPU,omitempty"`
+	// DefaultMemory is the memory limit given to containers that do not set one.
+	// +optional
+	DefaultMemory string `json:"defaultMemory,omitempty"`
+	// DefaultRequestCPU is the CPU request given to containers that do not set one.
+	// +optional
+	DefaultRequestCPU string `json:"defaultRequestCPU,omitempty"`
+	// DefaultRequestMemory is the memory request given to containers that do not set one.
+	// +optional
+	DefaultRequestMemory string `json:"defaultRequestMemory,omitempty"`
+	// MaxCPU is the largest CPU limit a single container may set.
+	// +optional
+	MaxCPU string `json:"maxCPU,omitempty"`
+	// MaxMemory is the largest memory limit a single container may set.
+	// +optional
+	MaxMemory string `json:"maxMemory,omitempty"`
+}
+
+// TenantTierSpec defines the resources granted to tenants of a tier.
+type TenantTierSpec struct {
+	// ResourceQuota is copied into spec.resourceQuota of tenants of this tier
+	// by the defaulting webhook, for every field the Tenant does not set.
+	// +optional
+	ResourceQuota ResourceQuota `json:"resourceQuota,omitempty"`
+
+	// LimitRange defaults container requests/limits in tenant namespaces.
+	// +optional
+	LimitRange TierLimitRange `json:"limitRange,omitempty"`
+
+	// PriorityClassName is the PriorityClass workloads of this tier run with.
+	// Pods in tenant namespaces that do not set a priorityClassName get it at admission.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// MaxNamespaces caps how many tenant namespaces may be provisioned with this tier.
+	// Zero means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxNamespaces int32 `json:"maxNamespaces,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// TenantTier is the Schema for the tenanttiers API.
+// Its name is what Tenants reference in spec.tier.
+type TenantTier struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the resources granted by this tier
+	// +required
+	Spec TenantTierSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenantTierList contains a list of TenantTier
+type TenantTierList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []TenantTier `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantTier{}, &TenantTierList{})
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -38,7 +39,11 @@ import (
 	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
 	"github.com/shieldx-bot/shieldx-platform/internal/config/dotenv"
 	"github.com/shieldx-bot/shieldx-platform/internal/controller"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/notify"
+	webhookv1 "github.com/shieldx-bot/shieldx-platform/internal/webhook/v1"
 	webhookv1alpha1 "github.com/shieldx-bot/shieldx-platform/internal/webhook/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -163,6 +168,18 @@ func main() {
 		metricsServerOptions.KeyName = metricsCertKey
 	}
 
+	// COSIGN_KEYRING names a Secret or ConfigMap of trusted signing keys that
+	// is watched for rotation through a cache of its own.
+	var keyringSource *controller.KeyringSource
+	if ref := os.Getenv("COSIGN_KEYRING"); ref != "" {
+		src, err := controller.ParseKeyringSource(ref)
+		if err != nil {
+			setupLog.Error(err, "unable to parse COSIGN_KEYRING")
+			os.Exit(1)
+		}
+		keyringSource = &src
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
@@ -187,19 +204,98 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := (&controller.TenantReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	// The signature scanner and the image webhooks share one verifier and cache.
+	imagePolicy, err := verifyimage.PolicyFromEnv()
+	if err != nil {
+		setupLog.Error(err, "unable to load image signature policy")
+		os.Exit(1)
+	}
+	imageVerifier := verifyimage.NewCosignVerifier(verifyimage.NewCacheFromEnv())
+	// SIGSTORE_TRUSTED_ROOT points at a trusted_root.json so verification works offline.
+	if path := os.Getenv("SIGSTORE_TRUSTED_ROOT"); path != "" {
+		if imageVerifier.TrustedRoot, err = verifyimage.LoadTrustedRoot(path); err != nil {
+			setupLog.Error(err, "unable to load Sigstore trusted root")
+			os.Exit(1)
+		}
+	}
+	// Air-gapped clusters: Rekor keys from mounted files, bundles checked locally.
+	if paths := os.Getenv("COSIGN_REKOR_PUBLIC_KEYS"); paths != "" {
+		if imageVerifier.RekorPubKeys, err = verifyimage.LoadRekorPublicKeys(strings.Split(paths, ",")...); err != nil {
+			setupLog.Error(err, "unable to load Rekor public keys")
+			os.Exit(1)
+		}
+	}
+	imageVerifier.Offline = os.Getenv("COSIGN_OFFLINE") == "true"
+	if imageVerifier.Mirrors, err = verifyimage.ParseMirrors(os.Getenv("COSIGN_REGISTRY_MIRRORS")); err != nil {
+		setupLog.Error(err, "unable to parse registry mirrors")
+		os.Exit(1)
+	}
+	if keyringSource != nil {
+		imagePolicy.Keyring = verifyimage.NewKeyring()
+		if err := (&controller.KeyringReconciler{
+			Keyring: imagePolicy.Keyring,
+			Source:  *keyringSource,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Keyring")
+			os.Exit(1)
+		}
+	}
+
+	if len(imagePolicy.Keys) == 0 && imagePolicy.Keyring == nil && len(imagePolicy.Identities) == 0 {
+		setupLog.Info("No default image signing key or identity configured; " +
+			"images no TenantImagePolicy or ClusterImagePolicy matches are rejected")
+	}
+
+	// TenantImagePolicies and ClusterImagePolicies refine the env-configured default per image.
+	imagePolicies := &imagepolicy.Resolver{Client: mgr.GetClient(), Default: imagePolicy}
+	// SHIELDX_SIGNATURE_ACTION=Quarantine isolates failing pods instead of deleting them.
+	switch action := os.Getenv("SHIELDX_SIGNATURE_ACTION"); action {
+	case "", platformv1alpha1.ImagePolicyActionDelete, platformv1alpha1.ImagePolicyActionQuarantine:
+		imagePolicies.DefaultAction = action
+	default:
+		setupLog.Error(nil, "invalid SHIELDX_SIGNATURE_ACTION (expected Delete or Quarantine)", "value", action)
+		os.Exit(1)
+	}
+
+	tenantReconciler := &controller.TenantReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("tenant-controller"),
+		Notify:   notify.SendMessageTelegram,
+
+		Verifier:      imageVerifier,
+		ImagePolicies: imagePolicies,
+	}
+	if err := tenantReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Tenant")
 		os.Exit(1)
 	}
+	// Verify pod images as they appear; the Tenant scanner's periodic full scan catches the rest.
+	if err := (&controller.PodImageReconciler{
+		Client:  mgr.GetClient(),
+		Scanner: tenantReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PodImage")
+		os.Exit(1)
+	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err := webhookv1alpha1.SetupTenantWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Tenant")
 			os.Exit(1)
 		}
+		if err := webhookv1.SetupWorkloadImageWebhookWithManager(mgr, imageVerifier, imagePolicies); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "WorkloadImage")
+			os.Exit(1)
+		}
+		if err := webhookv1.SetupPodDigestWebhookWithManager(mgr, imageVerifier, imagePolicies); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PodDigest")
+			os.Exit(1)
+		}
+		if err := webhookv1.SetupPodPriorityWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PodPriority")
+			os.Exit(1)
+		}
 
 	}
 	// +kubebuilder:scaffold:builder
@@ -10,6 +10,10 @@ import (
 	"github.com/shieldx-bot/shieldx-platform/internal/webhook/k8s"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ResourceQuota struct {
@@ -175,8 +179,54 @@ func main() {
 
 	_ = tenantCreateCmd.MarkFlagRequired("owners")
 
+	// Leaf: shieldctl tenant status TENANT_NAME
+	var tenantNamespace string
+	var tenantStatusOutput string
+	tenantStatusCmd := &cobra.Command{
+		Use:   "status TENANT_NAME",
+		Short: "Show tenant provisioning progress",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, cfg, err := k8s.GetClientset()
+			if err != nil {
+				return err
+			}
+			scheme := runtime.NewScheme()
+			if err := platformv1alpha1.AddToScheme(scheme); err != nil {
+				return err
+			}
+			c, err := client.New(cfg, client.Options{Scheme: scheme})
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+
+			var tenant platformv1alpha1.Tenant
+			if err := c.Get(cmd.Context(), client.ObjectKey{Namespace: tenantNamespace, Name: args[0]}, &tenant); err != nil {
+				return fmt.Errorf("failed to get tenant %q: %w", args[0], err)
+			}
+
+			switch strings.ToLower(strings.TrimSpace(tenantStatusOutput)) {
+			case "", "text":
+				printTenantStatus(&tenant)
+				return nil
+			case "json":
+				b, err := json.MarshalIndent(tenant.Status, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+				return nil
+			default:
+				return fmt.Errorf("invalid --output %q (expected text|json)", tenantStatusOutput)
+			}
+		},
+	}
+	tenantStatusCmd.Flags().StringVarP(&tenantNamespace, "namespace", "n", "default", "Namespace of the Tenant object")
+	tenantStatusCmd.Flags().StringVarP(&tenantStatusOutput, "output", "o", "text", "Output format (text|json)")
+
 	// Wire tree
 	tenantCmd.AddCommand(tenantCreateCmd)
+	tenantCmd.AddCommand(tenantStatusCmd)
 	rootCmd.AddCommand(tenantCmd)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -184,6 +234,46 @@ func main() {
 	}
 }
 
+// tenantStatusSteps lists the conditions shown by "shieldctl tenant status", in provisioning order.
+var tenantStatusSteps = []struct {
+	condition string
+	label     string
+}{
+	{platformv1alpha1.TenantConditionTierResolved, "Tier resolved"},
+	{platformv1alpha1.TenantConditionNamespaceReady, "Namespace created"},
+	{platformv1alpha1.TenantConditionQuotaReady, "Resource quota applied"},
+	{platformv1alpha1.TenantConditionNetworkPolicyReady, "Network isolation enabled"},
+	{platformv1alpha1.TenantConditionRBACReady, "Access granted to owners"},
+	{platformv1alpha1.TenantConditionImagePolicyEnforced, "Image security enforced"},
+}
+
+func printTenantStatus(tenant *platformv1alpha1.Tenant) {
+	for _, step := range tenantStatusSteps {
+		c := meta.FindStatusCondition(tenant.Status.Conditions, step.condition)
+		switch {
+		case c == nil:
+			fmt.Printf("… %s\n", step.label)
+		case c.Status == metav1.ConditionTrue:
+			fmt.Printf("✔ %s\n", step.label)
+		case c.Status == metav1.ConditionFalse:
+			fmt.Printf("✖ %s: %s (%s)\n", step.label, c.Message, c.Reason)
+		default:
+			fmt.Printf("… %s: %s\n", step.label, c.Message)
+		}
+	}
+	fmt.Println()
+
+	phase := tenant.Status.Phase
+	if phase == "" {
+		phase = platformv1alpha1.TenantPhasePending
+	}
+	if phase == platformv1alpha1.TenantPhaseReady {
+		fmt.Printf("Tenant %s is READY (namespace %s)\n", tenant.Name, tenant.Status.Namespace)
+		return
+	}
+	fmt.Printf("Tenant %s is %s\n", tenant.Name, strings.ToUpper(phase))
+}
+
 // apiVersion: platform.shieldx.io/v1alpha1
 // kind: Tenant
 // metadata:
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Kinds of object a keyring can be loaded from.
+const (
+	KeyringKindSecret    = "Secret"
+	KeyringKindConfigMap = "ConfigMap"
+)
+
+// KeyringSource names the Secret or ConfigMap holding the trusted signing keys.
+type KeyringSource struct {
+	Kind string
+	types.NamespacedName
+}
+
+// ParseKeyringSource parses "Secret/<namespace>/<name>" or "ConfigMap/<namespace>/<name>".
+func ParseKeyringSource(s string) (KeyringSource, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" ||
+		(parts[0] != KeyringKindSecret && parts[0] != KeyringKindConfigMap) {
+		return KeyringSource{}, fmt.Errorf("invalid keyring %q: expected Secret/<namespace>/<name> or ConfigMap/<namespace>/<name>", s)
+	}
+	return KeyringSource{Kind: parts[0], NamespacedName: types.NamespacedName{Namespace: parts[1], Name: parts[2]}}, nil
+}
+
+// Object returns an empty object of the source kind.
+func (s KeyringSource) Object() client.Object {
+	if s.Kind == KeyringKindConfigMap {
+		return &corev1.ConfigMap{}
+	}
+	return &corev1.Secret{}
+}
+
+// KeyringReconciler keeps a verifyimage.Keyring in sync with its source, so
+// signing keys rotate without restarting the manager.
+type KeyringReconciler struct {
+	// Reader reads the source. SetupWithManager defaults it to a cache of its
+	// own that holds only the source, so the manager cache stays unrestricted.
+	Reader  client.Reader
+	Keyring *verifyimage.Keyring
+	Source  KeyringSource
+}
+
+// The manager may only read keyrings in its own namespace; a keyring elsewhere
+// needs a Role granting the same access there.
+// +kubebuilder:rbac:groups="",namespace=shieldx-platform-system,resources=secrets;configmaps,verbs=get;list;watch
+
+// Reconcile loads the keys of the source into the keyring. A missing source
+// empties the keyring, so every image is rejected until keys are provided.
+func (r *KeyringReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	obj := r.Source.Object()
+	if err := r.Reader.Get(ctx, r.Source.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Keyring.Set(nil)
+			log.Info("Keyring source not found; no key is trusted", "kind", r.Source.Kind, "source", r.Source.String())
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var data map[string][]byte
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		data = o.Data
+	case *corev1.ConfigMap:
+		data = make(map[string][]byte, len(o.Data))
+		for k, v := range o.Data {
+			data[k] = []byte(v)
+		}
+	}
+
+	keys, err := verifyimage.ParseKeyring(data)
+	if err != nil {
+		log.Error(err, "Ignoring invalid keyring entries", "kind", r.Source.Kind, "source", r.Source.String())
+	}
+	r.Keyring.Set(keys)
+
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.Name)
+	}
+	log.Info("Loaded image signing keyring", "kind", r.Source.Kind, "source", r.Source.String(), "keys", names)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. The source is
+// watched through a dedicated cache limited to that one object.
+func (r *KeyringReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	keyringCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{
+			r.Source.Object(): {
+				Namespaces: map[string]cache.Config{r.Source.Namespace: {}},
+				Field:      fields.OneTermEqualSelector("metadata.name", r.Source.Name),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create keyring cache: %w", err)
+	}
+	if err := mgr.Add(keyringCache); err != nil {
+		return err
+	}
+	if r.Reader == nil {
+		r.Reader = keyringCache
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WatchesRawSource(source.Kind(keyringCache, r.Source.Object(), &handler.EnqueueRequestForObject{})).
+		Named("keyring").
+		Complete(r)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+)
+
+var _ = Describe("Keyring Controller", func() {
+	publicKeyPEM := func() []byte {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+		Expect(err).NotTo(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+
+	It("should load rotated keys and empty the keyring when the source goes away", func() {
+		source, err := ParseKeyringSource("Secret/default/cosign-keyring")
+		Expect(err).NotTo(HaveOccurred())
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: source.Name, Namespace: source.Namespace},
+			Data: map[string][]byte{
+				"2025.pub":       publicKeyPEM(),
+				"2025.not-after": []byte("2026-01-31T00:00:00Z"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+		keyring := verifyimage.NewKeyring()
+		r := &KeyringReconciler{Reader: k8sClient, Keyring: keyring, Source: source}
+		req := reconcile.Request{NamespacedName: source.NamespacedName}
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyring.Keys()).To(HaveLen(1))
+
+		By("adding the next key during rotation")
+		secret.Data["2026.pub"] = publicKeyPEM()
+		Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyring.Keys()).To(HaveLen(2))
+		Expect(keyring.Keys()[1].Name).To(Equal("2026"))
+
+		By("deleting the source")
+		Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyring.Keys()).To(BeEmpty())
+	})
+
+	It("should reject malformed keyring references", func() {
+		for _, ref := range []string{"cosign-keyring", "Pod/default/cosign", "Secret//cosign", "ConfigMap/default/"} {
+			_, err := ParseKeyringSource(ref)
+			Expect(err).To(HaveOccurred(), ref)
+		}
+	})
+})
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// transientRetry is how soon a pod whose images could not be verified is retried.
+const transientRetry = 30 * time.Second
+
+// PodImageReconciler verifies the images of pods in tenant namespaces as soon
+// as they are created or their images change. The first failing pod of a
+// workload is deleted or quarantined at once; after that, deleting its pods is
+// left to the scanner's periodic full scan, which counts consecutive failed
+// scans and also catches revoked signatures and policy changes.
+type PodImageReconciler struct {
+	client.Client
+	// Scanner verifies images and acts on failing workloads; its Verifier must
+	// be set and its SetupWithManager called first, which loads its settings.
+	Scanner *TenantReconciler
+
+	mu sync.Mutex
+	// checked holds the UID and images of each pod last verified, so pods are
+	// verified again only when their images change.
+	checked map[types.NamespacedName]string
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// Reconcile verifies a new or changed pod. Passing pods are left to the full
+// scan, which sees every replica of their workload.
+func (r *PodImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pod corev1.Pod
+	if err := r.Get(ctx, req.NamespacedName, &pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forget(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if pod.DeletionTimestamp != nil || r.Scanner.Verifier == nil {
+		return ctrl.Result{}, nil
+	}
+
+	tenant, err := r.tenantOf(ctx, pod.Namespace)
+	if err != nil || tenant == nil {
+		return ctrl.Result{}, err
+	}
+	checked := string(pod.UID) + "|" + strings.Join(collectPodImages(&pod.Spec), ",")
+	if r.isChecked(req.NamespacedName, checked) {
+		return ctrl.Result{}, nil
+	}
+
+	policies, err := r.Scanner.ImagePolicies.Policies(ctx, pod.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	workload, err := r.Scanner.resolveWorkload(ctx, &pod, workloadCache{})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	f := &finding{workload: workload, podEvent: true}
+	r.Scanner.verifyPod(ctx, &pod, policies, f)
+	if f.err == nil && f.unsure {
+		return ctrl.Result{RequeueAfter: transientRetry}, nil
+	}
+	r.markChecked(req.NamespacedName, checked)
+	if f.err != nil {
+		r.Scanner.enforce(ctx, tenant, f)
+	}
+	return ctrl.Result{}, nil
+}
+
+// tenantOf returns the Tenant whose scanned namespace is namespace, or nil.
+func (r *PodImageReconciler) tenantOf(ctx context.Context, namespace string) (*platformv1alpha1.Tenant, error) {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	name, tenantNS := ns.Labels[tenantNameLabel], ns.Labels[tenantNamespaceLabel]
+	if name == "" || tenantNS == "" {
+		return nil, nil
+	}
+
+	var tenant platformv1alpha1.Tenant
+	if err := r.Get(ctx, client.ObjectKey{Namespace: tenantNS, Name: name}, &tenant); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	// Same tenants as the full scan.
+	if tenantNamespaceName(&tenant) != namespace ||
+		strings.TrimSpace(strings.ToLower(tenant.Spec.Isolation)) != "namespace" {
+		return nil, nil
+	}
+	return &tenant, nil
+}
+
+func (r *PodImageReconciler) isChecked(key types.NamespacedName, checked string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.checked[key] == checked
+}
+
+func (r *PodImageReconciler) markChecked(key types.NamespacedName, checked string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.checked == nil {
+		r.checked = map[types.NamespacedName]string{}
+	}
+	r.checked[key] = checked
+}
+
+func (r *PodImageReconciler) forget(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checked, key)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PodImageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	inTenantNamespace := func(obj client.Object) bool {
+		return strings.HasPrefix(obj.GetNamespace(), tenantNamespacePrefix)
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return inTenantNamespace(e.Object) },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldPod, newPod := e.ObjectOld.(*corev1.Pod), e.ObjectNew.(*corev1.Pod)
+				return inTenantNamespace(newPod) &&
+					!slices.Equal(collectPodImages(&oldPod.Spec), collectPodImages(&newPod.Spec))
+			},
+			// Deletes only clear what was checked.
+			DeleteFunc:  func(e event.DeleteEvent) bool { return inTenantNamespace(e.Object) },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		})).
+		Named("podimage").
+		Complete(r)
+}
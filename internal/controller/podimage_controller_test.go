@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage/fake"
+)
+
+var _ = Describe("PodImage Controller", func() {
+	var (
+		c        client.Client
+		r        *PodImageReconciler
+		verifier *fake.Verifier
+	)
+
+	reconcilePod := func(namespace, name string) {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Namespace: namespace, Name: name}})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	exists := func(namespace, name string) bool {
+		err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &corev1.Pod{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+	replica := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "tenant-acme", UID: types.UID("uid-" + name),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web", UID: "uid-web", Controller: ptr.To(true),
+				}},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/unsigned:1.0"}}},
+		}
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(platformv1alpha1.AddToScheme(s)).To(Succeed())
+		labels := map[string]string{tenantNameLabel: "acme", tenantNamespaceLabel: "default"}
+		c = clientfake.NewClientBuilder().WithScheme(s).WithObjects(
+			&platformv1alpha1.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "acme", Namespace: "default"},
+				Spec:       platformv1alpha1.TenantSpec{Owners: []string{"alice"}, Isolation: "namespace"},
+			},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme", Labels: labels}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-spoofed"}},
+		).Build()
+		verifier = &fake.Verifier{Digests: map[string]string{"registry.example.com/signed:1.0": ""}}
+		r = &PodImageReconciler{
+			Client: c,
+			Scanner: &TenantReconciler{
+				Client:           c,
+				Verifier:         verifier,
+				ImagePolicies:    &imagepolicy.Resolver{Client: c},
+				FailureThreshold: 1,
+				Scan:             ScanOptions{RegistryQPS: -1},
+			},
+		}
+	})
+
+	It("should verify new pods in tenant namespaces without waiting for the full scan", func() {
+		for _, obj := range []client.Object{
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "signed", Namespace: "tenant-acme", UID: "uid-signed"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/signed:1.0"}}},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "unsigned", Namespace: "tenant-acme", UID: "uid-unsigned"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/unsigned:1.0"}}},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "unsigned", Namespace: "tenant-spoofed", UID: "uid-spoofed"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/unsigned:1.0"}}},
+			},
+		} {
+			Expect(c.Create(ctx, obj)).To(Succeed())
+		}
+
+		reconcilePod("tenant-acme", "signed")
+		reconcilePod("tenant-acme", "signed")
+		Expect(verifier.Calls()).To(HaveLen(1), "unchanged pods are verified once")
+		Expect(exists("tenant-acme", "signed")).To(BeTrue())
+
+		reconcilePod("tenant-spoofed", "unsigned")
+		Expect(exists("tenant-spoofed", "unsigned")).To(BeTrue(), "only namespaces of a Tenant are scanned")
+		Expect(verifier.Calls()).To(HaveLen(1))
+
+		reconcilePod("tenant-acme", "unsigned")
+		Expect(exists("tenant-acme", "unsigned")).To(BeFalse(), "a failing pod is acted on without waiting for the full scan")
+		Expect(r.Scanner.failures.count("uid-unsigned")).To(BeZero())
+		reconcilePod("tenant-acme", "unsigned")
+		Expect(r.checked).NotTo(HaveKey(client.ObjectKey{Namespace: "tenant-acme", Name: "unsigned"}))
+	})
+
+	It("should delete a failing pod on its pod event under a Delete policy", func() {
+		r.Scanner.FailureThreshold = 3
+		Expect(c.Create(ctx, &platformv1alpha1.TenantImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "delete", Namespace: "tenant-acme"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images: []string{"registry.example.com/unsigned*"},
+				Action: platformv1alpha1.ImagePolicyActionDelete,
+			},
+		})).To(Succeed())
+		Expect(c.Create(ctx, replica("web-a"))).To(Succeed())
+
+		reconcilePod("tenant-acme", "web-a")
+		Expect(exists("tenant-acme", "web-a")).To(BeFalse(), "a failed verification is a verdict, not an outage")
+		Expect(r.Scanner.failures.count("uid-web")).To(BeZero())
+	})
+
+	It("should not count pod events of replicas toward the failure threshold", func() {
+		r.Scanner.FailureThreshold = 2
+		Expect(c.Create(ctx, &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme", UID: "uid-web"},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: ptr.To[int32](3)},
+		})).To(Succeed())
+		names := []string{"web-a", "web-b", "web-c", "web-d"}
+		for _, name := range names[:3] {
+			Expect(c.Create(ctx, replica(name))).To(Succeed())
+			reconcilePod("tenant-acme", name)
+		}
+		Expect(exists("tenant-acme", "web-a")).To(BeFalse(), "the first failing pod is deleted at once")
+		Expect(exists("tenant-acme", "web-b")).To(BeTrue(), "later pods wait for the full scans")
+
+		By("replacing a replica")
+		reconcilePod("tenant-acme", "web-a")
+		Expect(c.Create(ctx, replica("web-d"))).To(Succeed())
+		reconcilePod("tenant-acme", "web-d")
+		Expect(r.Scanner.failures.count("uid-web")).To(BeZero())
+		for _, name := range names[1:] {
+			Expect(exists("tenant-acme", name)).To(BeTrue())
+		}
+
+		By("failing consecutive full scans")
+		// The action on web-a doubled the threshold to 4 failed scans.
+		for range 3 {
+			Expect(r.Scanner.scanAndEnforcePodImages(ctx)).To(Succeed())
+		}
+		Expect(r.Scanner.failures.count("uid-web")).To(Equal(3))
+		Expect(exists("tenant-acme", "web-b")).To(BeTrue())
+		Expect(r.Scanner.scanAndEnforcePodImages(ctx)).To(Succeed())
+		for _, name := range names[1:] {
+			Expect(exists("tenant-acme", name)).To(BeFalse())
+		}
+	})
+
+	It("should quarantine failing pods on pod events under a Quarantine policy", func() {
+		r.Scanner.FailureThreshold = 3
+		Expect(c.Create(ctx, &platformv1alpha1.TenantImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "quarantine", Namespace: "tenant-acme"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images: []string{"registry.example.com/unsigned*"},
+				Action: platformv1alpha1.ImagePolicyActionQuarantine,
+			},
+		})).To(Succeed())
+		Expect(c.Create(ctx, &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme", UID: "uid-web"},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: ptr.To[int32](2)},
+		})).To(Succeed())
+		for _, name := range []string{"web-a", "web-b"} {
+			Expect(c.Create(ctx, replica(name))).To(Succeed())
+		}
+
+		reconcilePod("tenant-acme", "web-a")
+		var rs appsv1.ReplicaSet
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "tenant-acme", Name: "web"}, &rs)).To(Succeed())
+		Expect(*rs.Spec.Replicas).To(BeZero())
+		var p corev1.Pod
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "tenant-acme", Name: "web-a"}, &p)).To(Succeed())
+		Expect(p.Labels).To(HaveKeyWithValue(quarantineLabel, "true"))
+		Expect(exists("tenant-acme", "web-b")).To(BeTrue(), "scaling to zero removes the other replicas")
+	})
+})
@@ -18,37 +18,126 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
-	"github.com/shieldx-bot/shieldx-platform/internal/webhook/notify"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
 	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// tenantNameLabel and tenantNamespaceLabel are set on every object the
+	// controller manages and identify the Tenant it belongs to.
+	tenantNameLabel      = platformv1alpha1.TenantNameLabel
+	tenantNamespaceLabel = platformv1alpha1.TenantNamespaceLabel
+
+	// imagePolicyLabel opts the tenant namespace into image signature enforcement.
+	imagePolicyLabel   = platformv1alpha1.ImagePolicyLabel
+	imagePolicyEnforce = platformv1alpha1.ImagePolicyEnforce
+)
+
+// tenantNamespacePrefix starts the name of every tenant namespace.
+const tenantNamespacePrefix = "tenant-"
+
+// tenantNamespaceName is the namespace provisioned for a Tenant.
+func tenantNamespaceName(tenant *platformv1alpha1.Tenant) string {
+	return tenantNamespacePrefix + tenant.Name
+}
+
+// errNamespaceConflict is returned when the tenant namespace already exists
+// and belongs to someone else.
+var errNamespaceConflict = errors.New("tenant namespace conflict")
+
+// namespaceConflict explains why tenant may not adopt ns, an existing
+// namespace, or returns "" when the namespace is already the tenant's.
+// Tenants are namespaced but their namespace is named after the Tenant
+// alone, so a Tenant of the same name elsewhere must not take it over.
+// Retained and unmanaged namespaces can be handed over with AdoptAnnotation.
+func namespaceConflict(ns *corev1.Namespace, tenant *platformv1alpha1.Tenant) string {
+	name, namespace := ns.Labels[tenantNameLabel], ns.Labels[tenantNamespaceLabel]
+	adopt := ns.Annotations[platformv1alpha1.AdoptAnnotation] == tenant.Namespace+"/"+tenant.Name
+	switch {
+	case name == tenant.Name && namespace == tenant.Namespace:
+		return ""
+	case name == "" && namespace == "":
+		if adopt {
+			return ""
+		}
+		return fmt.Sprintf("namespace %s already exists and is not managed by a Tenant; annotate it %s=%s/%s to adopt it",
+			ns.Name, platformv1alpha1.AdoptAnnotation, tenant.Namespace, tenant.Name)
+	case ns.Labels[retainedLabel] == "true":
+		if adopt {
+			return ""
+		}
+		return fmt.Sprintf("namespace %s was retained from Tenant %s/%s; annotate it %s=%s/%s to adopt it",
+			ns.Name, namespace, name, platformv1alpha1.AdoptAnnotation, tenant.Namespace, tenant.Name)
+	default:
+		return fmt.Sprintf("namespace %s belongs to Tenant %s/%s", ns.Name, namespace, name)
+	}
+}
+
 // TenantReconciler reconciles a Tenant object
 type TenantReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Recorder emits Events on Tenants; optional.
+	Recorder record.EventRecorder
+	// Notify delivers teardown and image scan notifications, e.g. notify.SendMessageTelegram; optional.
+	Notify func(message string) error
+	// Verifier checks pod images against the policy ImagePolicies selects
+	// for them in the periodic scan, which is disabled when Verifier is nil.
+	Verifier      verifyimage.Verifier
+	ImagePolicies *imagepolicy.Resolver
+	// FailureThreshold is how many consecutive scans a workload must fail
+	// before an Enforce policy deletes or quarantines its pods; defaults to 3.
+	FailureThreshold int
+	// ScanInterval is the time between periodic full scans; defaults to 10m.
+	ScanInterval time.Duration
+	// Scan bounds the concurrency, registry load and duration of each scan.
+	Scan ScanOptions
+
+	failures   failureTracker
+	scanning   atomic.Bool
+	limitsOnce sync.Once
+	limits     *registryLimiter
 }
 
 // +kubebuilder:rbac:groups=platform.shieldx.io,resources=tenants,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=platform.shieldx.io,resources=tenants/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=platform.shieldx.io,resources=tenants/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch;delete
+// +kubebuilder:rbac:groups="",resources=resourcequotas;limitranges,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=platform.shieldx.io,resources=tenanttiers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=platform.shieldx.io,resources=tenantimagepolicies;clusterimagepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=admin;edit;view
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;replicasets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -62,11 +151,12 @@ type TenantReconciler struct {
 func (r *TenantReconciler) ensureNamespace(
 	ctx context.Context,
 	tenant *platformv1alpha1.Tenant,
-) error {
+	tier *platformv1alpha1.TenantTier,
+) (*corev1.Namespace, error) {
 
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "tenant-" + tenant.Name,
+			Name: tenantNamespaceName(tenant),
 		},
 	}
 
@@ -75,30 +165,118 @@ func (r *TenantReconciler) ensureNamespace(
 		r.Client,
 		ns,
 		func() error {
-			if ns.Labels == nil {
-				ns.Labels = map[string]string{}
+			if ns.ResourceVersion != "" {
+				if msg := namespaceConflict(ns, tenant); msg != "" {
+					return fmt.Errorf("%w: %s", errNamespaceConflict, msg)
+				}
+			}
+			setTenantLabels(ns, tenant)
+			delete(ns.Labels, retainedLabel)
+			delete(ns.Annotations, platformv1alpha1.AdoptAnnotation)
+			ns.Labels[tierLabel] = tier.Name
+			if tenant.Spec.EnforceImageSignatures {
+				ns.Labels[imagePolicyLabel] = imagePolicyEnforce
+			} else {
+				delete(ns.Labels, imagePolicyLabel)
 			}
-			ns.Labels["tenant"] = tenant.Name
 
-			return ctrl.SetControllerReference(tenant, ns, r.Scheme)
+			if tier.Spec.PriorityClassName != "" {
+				if ns.Annotations == nil {
+					ns.Annotations = map[string]string{}
+				}
+				ns.Annotations[priorityClassAnnotation] = tier.Spec.PriorityClassName
+			} else {
+				delete(ns.Annotations, priorityClassAnnotation)
+			}
+			return nil
 		},
 	)
 
-	return err
+	return ns, err
+}
+
+// markImagePolicy reports whether the namespace is labeled for image signature
+// enforcement and an image policy applies in it.
+func (r *TenantReconciler) markImagePolicy(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	ns *corev1.Namespace,
+) error {
+	if ns.Labels[imagePolicyLabel] != imagePolicyEnforce {
+		setCondition(tenant, platformv1alpha1.TenantConditionImagePolicyEnforced, metav1.ConditionFalse, "NotRequested",
+			fmt.Sprintf("Namespace %s is not labeled %s=%s", ns.Name, imagePolicyLabel, imagePolicyEnforce))
+		return nil
+	}
+	policies, err := r.ImagePolicies.Policies(ctx, ns.Name)
+	if err != nil {
+		return markStep(tenant, platformv1alpha1.TenantConditionImagePolicyEnforced, err, "", "")
+	}
+	if !policies.Trusts() {
+		setCondition(tenant, platformv1alpha1.TenantConditionImagePolicyEnforced, metav1.ConditionFalse, "NoPolicy",
+			fmt.Sprintf("No image policy trusts a signer in namespace %s, so every image is rejected", ns.Name))
+		return nil
+	}
+	setCondition(tenant, platformv1alpha1.TenantConditionImagePolicyEnforced, metav1.ConditionTrue, "PolicyEnforced",
+		fmt.Sprintf("Namespace %s is labeled %s=%s and image policies apply", ns.Name, imagePolicyLabel, imagePolicyEnforce))
+	return nil
+}
+
+// setTenantLabels links a child object to its Tenant.
+//
+// A namespaced Tenant cannot be the owner of a cluster-scoped Namespace nor of
+// objects in another namespace, so children are tracked by labels instead of
+// ownerReferences (see tenantForObject).
+func setTenantLabels(obj metav1.Object, tenant *platformv1alpha1.Tenant) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[tenantNameLabel] = tenant.Name
+	labels[tenantNamespaceLabel] = tenant.Namespace
+	obj.SetLabels(labels)
+}
+
+// tenantForObject maps an event on a labeled child object back to its Tenant.
+func tenantForObject(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, namespace := labels[tenantNameLabel], labels[tenantNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
 }
 
 // 👉 Namespace bị xóa tay → tự tạo lại
-// 👉 Tenant bị xóa → Namespace bị GC
+
+// desiredQuotaHard builds spec.hard for the tenant ResourceQuota from
+// spec.resourceQuota, which the defaulting webhook fills from the tier.
+// Every invalid quantity is reported in the returned error.
+func desiredQuotaHard(tenant *platformv1alpha1.Tenant) (corev1.ResourceList, error) {
+	spec := tenant.Spec.ResourceQuota
+	hard, problems := parseQuantities([]quantityField{
+		{corev1.ResourceRequestsCPU, "spec.resourceQuota.requestsCPU", spec.RequestsCPU},
+		{corev1.ResourceRequestsMemory, "spec.resourceQuota.requestsMemory", spec.RequestsMemory},
+		{corev1.ResourceLimitsCPU, "spec.resourceQuota.limitsCPU", spec.LimitsCPU},
+		{corev1.ResourceLimitsMemory, "spec.resourceQuota.limitsMemory", spec.LimitsMemory},
+		{corev1.ResourceRequestsStorage, "spec.resourceQuota.requestsStorage", spec.RequestsStorage},
+		{corev1.ResourcePods, "spec.resourceQuota.pods", spec.Pods},
+	})
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return hard, nil
+}
 
 func (r *TenantReconciler) ensureResourceQuota(
 	ctx context.Context,
 	tenant *platformv1alpha1.Tenant,
+	hard corev1.ResourceList,
 ) error {
 
 	quota := &corev1.ResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "tenant-quota",
-			Namespace: "tenant-" + tenant.Name,
+			Namespace: tenantNamespaceName(tenant),
 		},
 	}
 
@@ -107,13 +285,10 @@ func (r *TenantReconciler) ensureResourceQuota(
 		r.Client,
 		quota,
 		func() error {
-			quota.Spec.Hard = corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("4"),
-				corev1.ResourceMemory: resource.MustParse("8Gi"),
-				corev1.ResourcePods:   resource.MustParse("20"),
-			}
-
-			return ctrl.SetControllerReference(tenant, quota, r.Scheme)
+			setTenantLabels(quota, tenant)
+			// Replace the whole list so fields removed from the spec are dropped too.
+			quota.Spec.Hard = hard
+			return nil
 		},
 	)
 
@@ -122,288 +297,257 @@ func (r *TenantReconciler) ensureResourceQuota(
 
 // 👉 Quota bị sửa tay → controller sửa ngược lại
 // 👉 Đây chính là State Reconciliation
-func (r *TenantReconciler) ensureNetworkPolicy(
+func (r *TenantReconciler) ensureNetworkPolicies(
 	ctx context.Context,
 	tenant *platformv1alpha1.Tenant,
-) error {
+	peers tenantPeers,
+) (int, error) {
 
-	policy := &networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "default-deny",
-			Namespace: "tenant-" + tenant.Name,
-		},
-	}
+	desired := desiredNetworkPolicies(tenant, peers)
+	keep := make(map[string]struct{}, len(desired))
 
-	_, err := controllerutil.CreateOrUpdate(
-		ctx,
-		r.Client,
-		policy,
-		func() error {
-			policy.Spec = networkingv1.NetworkPolicySpec{
-				PodSelector: metav1.LabelSelector{},
-				PolicyTypes: []networkingv1.PolicyType{
-					networkingv1.PolicyTypeIngress,
-					networkingv1.PolicyTypeEgress,
-				},
-			}
+	for _, want := range desired {
+		keep[want.Name] = struct{}{}
 
-			return ctrl.SetControllerReference(tenant, policy, r.Scheme)
-		},
-	)
+		policy := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      want.Name,
+				Namespace: tenantNamespaceName(tenant),
+			},
+		}
 
-	return err
+		_, err := controllerutil.CreateOrUpdate(
+			ctx,
+			r.Client,
+			policy,
+			func() error {
+				setTenantLabels(policy, tenant)
+				policy.Labels[componentLabel] = componentNetworkPolicy
+				policy.Spec = want.Spec
+				return nil
+			},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to apply NetworkPolicy %q: %w", want.Name, err)
+		}
+	}
+
+	// Prune policies generated for rules that were removed from the spec.
+	var existing networkingv1.NetworkPolicyList
+	if err := r.List(ctx, &existing,
+		client.InNamespace(tenantNamespaceName(tenant)),
+		client.MatchingLabels{tenantNameLabel: tenant.Name, componentLabel: componentNetworkPolicy},
+	); err != nil {
+		return 0, fmt.Errorf("failed to list tenant NetworkPolicies: %w", err)
+	}
+	for i := range existing.Items {
+		policy := &existing.Items[i]
+		if _, ok := keep[policy.Name]; ok {
+			continue
+		}
+		if err := r.Delete(ctx, policy); err != nil && !apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("failed to delete stale NetworkPolicy %q: %w", policy.Name, err)
+		}
+	}
+
+	return len(desired), nil
 }
 
 // 👉 Namespace mới tạo → mặc định bị deny network
 // 👉 Chuẩn security-by-default
-func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := logf.FromContext(ctx)
 
-	log.Info("Reconciling Tenant", "name", req.NamespacedName)
+// provision applies every child resource of the tenant and records the
+// outcome as conditions on tenant.Status. A spec problem stops provisioning
+// without an error: it is reported in a condition and the Tenant is
+// re-queued once it (or its TenantTier) is edited.
+func (r *TenantReconciler) provision(ctx context.Context, tenant *platformv1alpha1.Tenant) error {
+	log := logf.FromContext(ctx)
 
-	var tenant platformv1alpha1.Tenant
-	if err := r.Get(ctx, req.NamespacedName, &tenant); err != nil {
-		if apierrors.IsNotFound(err) {
-			return ctrl.Result{}, nil
-		}
-		return ctrl.Result{}, err
+	// Resolve tier
+	tier, err := r.resolveTier(ctx, tenant)
+	if err != nil {
+		return markStep(tenant, platformv1alpha1.TenantConditionTierResolved, err, "", "")
 	}
-	if err := r.Get(ctx, req.NamespacedName, &tenant); err != nil {
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+	if tier == nil {
+		msg := fmt.Sprintf("TenantTier %q does not exist", tierName(tenant))
+		setCondition(tenant, platformv1alpha1.TenantConditionTierResolved, metav1.ConditionFalse, "UnknownTier", msg)
+		return nil
 	}
-
-	// 2️⃣ Ensure Namespace
-	if err := r.ensureNamespace(ctx, &tenant); err != nil {
-		return ctrl.Result{}, err
+	full, err := r.tierIsFull(ctx, tenant, tier)
+	if err != nil {
+		return markStep(tenant, platformv1alpha1.TenantConditionTierResolved, err, "", "")
 	}
-
-	// 3️⃣ Ensure ResourceQuota
-	if err := r.ensureResourceQuota(ctx, &tenant); err != nil {
-		return ctrl.Result{}, err
+	if full {
+		msg := fmt.Sprintf("TenantTier %q allows at most %d namespaces", tier.Name, tier.Spec.MaxNamespaces)
+		setCondition(tenant, platformv1alpha1.TenantConditionTierResolved, metav1.ConditionFalse, "TierFull", msg)
+		return nil
 	}
+	setCondition(tenant, platformv1alpha1.TenantConditionTierResolved, metav1.ConditionTrue, "TierFound", fmt.Sprintf("Using TenantTier %q", tier.Name))
 
-	// 4️⃣ Ensure NetworkPolicy
-	if err := r.ensureNetworkPolicy(ctx, &tenant); err != nil {
-		return ctrl.Result{}, err
+	// Ensure Namespace
+	ns, err := r.ensureNamespace(ctx, tenant, tier)
+	if errors.Is(err, errNamespaceConflict) {
+		// Retried with backoff, as the owner may still go away.
+		setCondition(tenant, platformv1alpha1.TenantConditionNamespaceReady, metav1.ConditionFalse, "NamespaceConflict", err.Error())
+		return err
 	}
-
-	// Only manage namespace-isolated tenants.
-	if tenant.Spec.Isolation != "namespace" {
-		return ctrl.Result{}, nil
+	if err != nil {
+		return markStep(tenant, platformv1alpha1.TenantConditionNamespaceReady, err, "", "")
 	}
-
-	tenantNS := "tenant-" + tenant.Name
-
-	// Ensure Namespace exists.
-	var ns corev1.Namespace
-	if err := r.Get(ctx, client.ObjectKey{Name: tenantNS}, &ns); err != nil {
-		if apierrors.IsNotFound(err) {
-			ns = corev1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: tenantNS,
-				},
-			}
-			if err := r.Create(ctx, &ns); err != nil && !apierrors.IsAlreadyExists(err) {
-				return ctrl.Result{}, fmt.Errorf("failed to create namespace %q: %w", tenantNS, err)
-			}
-			log.Info("Created tenant namespace", "tenant", tenant.Name, "namespace", tenantNS)
-			return ctrl.Result{Requeue: true}, nil
-		}
-		return ctrl.Result{}, fmt.Errorf("failed to get namespace %q: %w", tenantNS, err)
+	if ns.Status.Phase == corev1.NamespaceTerminating {
+		setCondition(tenant, platformv1alpha1.TenantConditionNamespaceReady, metav1.ConditionFalse, "Terminating",
+			fmt.Sprintf("Namespace %s is terminating", ns.Name))
+		return nil
+	}
+	setCondition(tenant, platformv1alpha1.TenantConditionNamespaceReady, metav1.ConditionTrue, "NamespaceActive",
+		fmt.Sprintf("Namespace %s is active", ns.Name))
+	tenant.Status.Namespace = ns.Name
+	if err := r.markImagePolicy(ctx, tenant, ns); err != nil {
+		return err
 	}
 
-	// Ensure: when Namespace is deleted, Tenant is garbage-collected.
-	// OwnerReference requires Namespace UID (available after Namespace exists).
-	changed := false
-	refs := tenant.GetOwnerReferences()
-
-	found := false
-	for i := range refs {
-		if refs[i].APIVersion == "v1" && refs[i].Kind == "Namespace" && refs[i].Name == ns.Name {
-			found = true
-			if refs[i].UID != ns.UID {
-				refs[i].UID = ns.UID
-				changed = true
-			}
-			break
-		}
+	// Ensure ResourceQuota and LimitRange
+	hard, err := desiredQuotaHard(tenant)
+	if err != nil {
+		// Bad quantities are a spec problem: report them and wait for the Tenant to be edited.
+		log.Info("Invalid resourceQuota in Tenant spec", "tenant", tenant.Name, "reason", err.Error())
+		setCondition(tenant, platformv1alpha1.TenantConditionQuotaReady, metav1.ConditionFalse, "InvalidQuantity", err.Error())
+		return nil
 	}
-	if !found {
-		refs = append(refs, metav1.OwnerReference{
-			APIVersion: "v1",
-			Kind:       "Namespace",
-			Name:       ns.Name,
-			UID:        ns.UID,
-		})
-		changed = true
+	limits, problems := desiredLimitRange(tier)
+	if len(problems) > 0 {
+		msg := strings.Join(problems, "; ")
+		log.Info("Invalid limitRange in TenantTier", "tier", tier.Name, "reason", msg)
+		setCondition(tenant, platformv1alpha1.TenantConditionQuotaReady, metav1.ConditionFalse, "InvalidQuantity", msg)
+		return nil
 	}
-
-	if changed {
-		tenant.SetOwnerReferences(refs)
-		if err := r.Update(ctx, &tenant); err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed to update tenant ownerReferences: %w", err)
-		}
-		log.Info("Updated Tenant ownerReference to Namespace", "tenant", tenant.Name, "namespace", tenantNS)
-		return ctrl.Result{Requeue: true}, nil
+	err = r.ensureResourceQuota(ctx, tenant, hard)
+	if err == nil {
+		err = r.ensureLimitRange(ctx, tenant, limits)
 	}
-
-	return ctrl.Result{}, nil
-}
-
-func (r *TenantReconciler) Start(ctx context.Context) error {
-	log := logf.Log.WithName("tenant-signature-scanner")
-
-	// Periodic scan interval. Keep it reasonably large to avoid hammering Rekor/registry.
-	// You can override via env, e.g. SHIELDX_SIGNATURE_SCAN_INTERVAL=2m
-	interval := 20 * time.Second
-	err2s := notify.SendMessageTelegram("Bắt đầu scan chữ ký hình ảnh với khoảng thời gian: " + interval.String())
-	if err2s != nil {
-		// Don't block the admission request if Telegram is down/misconfigured.
-		log.Error(err2s, "failed to send Telegram notification about signature scan start")
+	if err := markStep(tenant, platformv1alpha1.TenantConditionQuotaReady, err,
+		"QuotaApplied", "ResourceQuota and LimitRange match the spec and tier"); err != nil {
+		return err
 	}
 
-	if v := strings.TrimSpace(strings.ToLower(getenv("SHIELDX_SIGNATURE_SCAN_INTERVAL", ""))); v != "" {
-		if d, err := time.ParseDuration(v); err == nil && d > 0 {
-			interval = d
-		} else {
-			log.Error(err, "invalid SHIELDX_SIGNATURE_SCAN_INTERVAL; using default", "value", v, "default", interval.String())
-		}
+	// Ensure NetworkPolicies
+	peers, err := r.resolveTenantPeers(ctx, tenant)
+	if err != nil {
+		return markStep(tenant, platformv1alpha1.TenantConditionNetworkPolicyReady, err, "", "")
 	}
-
-	log.Info("starting periodic image signature enforcement", "interval", interval.String())
-	t := time.NewTicker(interval)
-	defer t.Stop()
-
-	// Run once at startup, then on each tick.
-	if err := r.scanAndEnforcePodImages(ctx); err != nil {
-		log.Error(err, "initial scan failed")
+	applied, err := r.ensureNetworkPolicies(ctx, tenant, peers)
+	if err := markStep(tenant, platformv1alpha1.TenantConditionNetworkPolicyReady, err,
+		"PoliciesApplied", fmt.Sprintf("%d NetworkPolicies match the spec", applied)); err != nil {
+		return err
 	}
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Info("stopping periodic image signature enforcement")
-			return nil
-		case <-t.C:
-			if err := r.scanAndEnforcePodImages(ctx); err != nil {
-				log.Error(err, "periodic scan failed")
-			}
-		}
+	if len(peers.blocked) > 0 {
+		// Other rules are applied; the skipped ones return once the peer consents.
+		msg := tenantPeerMessage(tenant, peers.blocked)
+		log.Info("Tenant peer has not consented", "tenant", tenant.Name, "reason", msg)
+		setCondition(tenant, platformv1alpha1.TenantConditionNetworkPolicyReady, metav1.ConditionFalse, "TenantPeerNotConsented", msg)
 	}
-}
 
-func (r *TenantReconciler) scanAndEnforcePodImages(ctx context.Context) error {
-	log := logf.Log.WithName("tenant-signature-scanner")
-
-	var tenants platformv1alpha1.TenantList
-	if err := r.List(ctx, &tenants); err != nil {
-		return fmt.Errorf("list tenants: %w", err)
+	// Ensure RoleBindings
+	bindings := tenantBindings(tenant)
+	subjects := make([][]rbacv1.Subject, len(bindings))
+	var invalid []string
+	for i, b := range bindings {
+		var problems []string
+		subjects[i], problems = bindingSubjects(b, ns.Name)
+		invalid = append(invalid, problems...)
 	}
-
-	for _, tenant := range tenants.Items {
-		if strings.TrimSpace(strings.ToLower(tenant.Spec.Isolation)) != "namespace" {
-			continue
-		}
-		tenantNS := "tenant-" + tenant.Name
-
-		var pods corev1.PodList
-		if err := r.List(ctx, &pods, client.InNamespace(tenantNS)); err != nil {
-			// Namespace may not exist yet; don't fail the entire scan.
-			if apierrors.IsNotFound(err) {
-				continue
-			}
-			log.Error(err, "failed to list pods in tenant namespace", "tenant", tenant.Name, "namespace", tenantNS)
-			continue
-		}
-
-		for i := range pods.Items {
-			pod := &pods.Items[i]
-			if pod.DeletionTimestamp != nil {
-				continue
-			}
-
-			images := collectPodImages(pod)
-			for _, image := range images {
-				if strings.TrimSpace(image) == "" {
-					continue
-				}
-				if err := verifyimage.VerifyImageSignature(image); err == nil {
-					continue
-				} else {
-					// Enforcement action: delete pod immediately.
-					delErr := r.Delete(ctx, pod, client.GracePeriodSeconds(0))
-					if delErr != nil && !apierrors.IsNotFound(delErr) {
-						log.Error(delErr, "failed to delete non-compliant pod", "tenant", tenant.Name, "namespace", tenantNS, "pod", pod.Name, "image", image)
-					} else {
-						log.Info("deleted non-compliant pod (signature verification failed)", "tenant", tenant.Name, "namespace", tenantNS, "pod", pod.Name, "image", image)
-					}
-
-					msg := fmt.Sprintf(
-						"[ShieldX] Deleted pod due to image signature verification failure\nTenant: %s\nNamespace: %s\nPod: %s\nImage: %s\nError: %v",
-						tenant.Name,
-						tenantNS,
-						pod.Name,
-						image,
-						err,
-					)
-					if nerr := notify.SendMessageTelegram(msg); nerr != nil {
-						log.Error(nerr, "failed to send Telegram notification", "tenant", tenant.Name, "namespace", tenantNS, "pod", pod.Name)
-					}
-
-					// One failing image is enough to delete the pod; don't spam per container.
-					break
-				}
-			}
+	if len(invalid) > 0 {
+		msg := strings.Join(invalid, "; ")
+		log.Info("Invalid identities in Tenant spec", "tenant", tenant.Name, "reason", msg)
+		setCondition(tenant, platformv1alpha1.TenantConditionRBACReady, metav1.ConditionFalse, "InvalidSubject", msg)
+		return nil
+	}
+	for i, b := range bindings {
+		if err = r.ensureRoleBinding(ctx, tenant, b, subjects[i]); err != nil {
+			break
 		}
 	}
+	if err := markStep(tenant, platformv1alpha1.TenantConditionRBACReady, err,
+		"BindingsApplied", "RoleBindings match owners, members and viewers"); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func collectPodImages(pod *corev1.Pod) []string {
-	seen := make(map[string]struct{}, 8)
-	out := make([]string, 0, 8)
+func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
 
-	add := func(img string) {
-		img = strings.TrimSpace(img)
-		if img == "" {
-			return
-		}
-		if _, ok := seen[img]; ok {
-			return
+	log.Info("Reconciling Tenant", "name", req.NamespacedName)
+
+	var tenant platformv1alpha1.Tenant
+	if err := r.Get(ctx, req.NamespacedName, &tenant); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
 		}
-		seen[img] = struct{}{}
-		out = append(out, img)
+		return ctrl.Result{}, err
 	}
 
-	for _, c := range pod.Spec.InitContainers {
-		add(c.Image)
+	// 1️⃣ Deleted tenants are torn down by the finalizer; live ones get it added.
+	// Teardown, not a namespace ownerReference, ties the Tenant to its namespace.
+	if !tenant.DeletionTimestamp.IsZero() {
+		return r.teardown(ctx, &tenant)
 	}
-	for _, c := range pod.Spec.Containers {
-		add(c.Image)
+	added := controllerutil.AddFinalizer(&tenant, tenantFinalizer)
+	if dropped := dropNamespaceOwner(&tenant); added || dropped {
+		if err := r.Update(ctx, &tenant); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update tenant finalizer: %w", err)
+		}
+	}
+
+	// 2️⃣ Provision child resources, recording a condition for each step.
+	original := tenant.DeepCopy()
+	err := r.provision(ctx, &tenant)
+	if statusErr := r.updateStatus(ctx, original, &tenant); statusErr != nil {
+		return ctrl.Result{}, errors.Join(err, statusErr)
 	}
-	return out
+	return ctrl.Result{}, err
 }
 
-func getenv(key, def string) string {
-	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
-		return v
+// dropNamespaceOwner removes the ownerReference to the tenant namespace set by
+// earlier versions, through which deleting the namespace garbage-collected
+// the Tenant. The finalizer and teardown now own that relationship.
+func dropNamespaceOwner(tenant *platformv1alpha1.Tenant) bool {
+	refs := tenant.GetOwnerReferences()
+	kept := slices.DeleteFunc(slices.Clone(refs), func(ref metav1.OwnerReference) bool {
+		return ref.APIVersion == "v1" && ref.Kind == "Namespace"
+	})
+	if len(kept) == len(refs) {
+		return false
 	}
-	return def
+	tenant.SetOwnerReferences(kept)
+	return true
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Start the periodic enforcement loop alongside the controller.
+	r.loadScanSettings()
 	if err := mgr.Add(r); err != nil {
 		return err
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &platformv1alpha1.Tenant{}, tenantTierField,
+		func(obj client.Object) []string {
+			return []string{tierName(obj.(*platformv1alpha1.Tenant))}
+		}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&platformv1alpha1.Tenant{}).
-		Owns(&corev1.Namespace{}).
-		Owns(&corev1.ResourceQuota{}).
-		Owns(&networkingv1.NetworkPolicy{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(tenantForObject)).
+		Watches(&corev1.ResourceQuota{}, handler.EnqueueRequestsFromMapFunc(tenantForObject)).
+		Watches(&corev1.LimitRange{}, handler.EnqueueRequestsFromMapFunc(tenantForObject)).
+		Watches(&networkingv1.NetworkPolicy{}, handler.EnqueueRequestsFromMapFunc(tenantForObject)).
+		Watches(&rbacv1.RoleBinding{}, handler.EnqueueRequestsFromMapFunc(tenantForObject)).
+		Watches(&platformv1alpha1.TenantTier{}, handler.EnqueueRequestsFromMapFunc(r.tenantsForTier)).
+		Watches(&platformv1alpha1.Tenant{}, handler.EnqueueRequestsFromMapFunc(r.tenantsForPeer)).
 		Named("tenant").
 		Complete(r)
 }
@@ -21,8 +21,14 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,15 +49,43 @@ var _ = Describe("Tenant Controller", func() {
 		tenant := &platformv1alpha1.Tenant{}
 
 		BeforeEach(func() {
+			By("creating the TenantTier referenced by the Tenant")
+			tier := &platformv1alpha1.TenantTier{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: "silver"}, tier)
+			if err != nil && errors.IsNotFound(err) {
+				tier = &platformv1alpha1.TenantTier{
+					ObjectMeta: metav1.ObjectMeta{Name: "silver"},
+					Spec: platformv1alpha1.TenantTierSpec{
+						ResourceQuota: platformv1alpha1.ResourceQuota{
+							RequestsCPU:  "4",
+							LimitsMemory: "16Gi",
+							Pods:         "20",
+						},
+						LimitRange: platformv1alpha1.TierLimitRange{
+							DefaultCPU: "500m",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, tier)).To(Succeed())
+			}
+
 			By("creating the custom resource for the Kind Tenant")
-			err := k8sClient.Get(ctx, typeNamespacedName, tenant)
+			err = k8sClient.Get(ctx, typeNamespacedName, tenant)
 			if err != nil && errors.IsNotFound(err) {
 				resource := &platformv1alpha1.Tenant{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: platformv1alpha1.TenantSpec{
+						Owners:    []string{"owner@shieldx.io"},
+						Tier:      "silver",
+						Isolation: "namespace",
+						ResourceQuota: platformv1alpha1.ResourceQuota{
+							RequestsCPU: "500m",
+							Pods:        "5",
+						},
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -61,10 +95,20 @@ var _ = Describe("Tenant Controller", func() {
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &platformv1alpha1.Tenant{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				// Already deleted by the test.
+				return
+			}
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance Tenant")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			// envtest has no namespace controller, so teardown never completes; release the Tenant directly.
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil && len(resource.Finalizers) > 0 {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			}
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
@@ -80,5 +124,545 @@ var _ = Describe("Tenant Controller", func() {
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
+
+		It("should drop the namespace ownerReference set by earlier versions", func() {
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Namespace", Name: ns.Name, UID: ns.UID}}
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			Expect(tenant.OwnerReferences).To(BeEmpty(), "deleting the namespace must not garbage-collect the Tenant")
+		})
+
+		It("should build the ResourceQuota from the spec alone", func() {
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			quota := &corev1.ResourceQuota{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      "tenant-quota",
+				Namespace: "tenant-" + resourceName,
+			}, quota)).To(Succeed())
+
+			Expect(quota.Spec.Hard).To(HaveKeyWithValue(corev1.ResourceRequestsCPU, resource.MustParse("500m")))
+			Expect(quota.Spec.Hard).To(HaveKeyWithValue(corev1.ResourcePods, resource.MustParse("5")))
+			Expect(quota.Spec.Hard).NotTo(HaveKey(corev1.ResourceLimitsMemory), "the defaulting webhook fills tier quota")
+
+			lr := &corev1.LimitRange{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      "tenant-limits",
+				Namespace: "tenant-" + resourceName,
+			}, lr)).To(Succeed())
+			Expect(lr.Spec.Limits).To(HaveLen(1))
+			Expect(lr.Spec.Limits[0].Default).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("500m")))
+		})
+
+		It("should report provisioning progress in status", func() {
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			Expect(tenant.Status.Namespace).To(Equal("tenant-" + resourceName))
+			Expect(tenant.Status.Phase).To(Equal(platformv1alpha1.TenantPhaseReady))
+			for _, t := range []string{
+				platformv1alpha1.TenantConditionNamespaceReady,
+				platformv1alpha1.TenantConditionQuotaReady,
+				platformv1alpha1.TenantConditionNetworkPolicyReady,
+				platformv1alpha1.TenantConditionRBACReady,
+			} {
+				cond := meta.FindStatusCondition(tenant.Status.Conditions, t)
+				Expect(cond).NotTo(BeNil(), t)
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue), t)
+				Expect(cond.ObservedGeneration).To(Equal(tenant.Generation), t)
+			}
+
+			By("leaving image signature enforcement to the Tenants that ask for it")
+			cond := meta.FindStatusCondition(tenant.Status.Conditions, platformv1alpha1.TenantConditionImagePolicyEnforced)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("NotRequested"))
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			Expect(ns.Labels).NotTo(HaveKey(platformv1alpha1.ImagePolicyLabel))
+		})
+
+		It("should label the namespace only when image signatures are enforced", func() {
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.EnforceImageSignatures = true
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			Expect(ns.Labels).To(HaveKeyWithValue(platformv1alpha1.ImagePolicyLabel, platformv1alpha1.ImagePolicyEnforce))
+
+			By("reporting that no policy trusts a signer")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			cond := meta.FindStatusCondition(tenant.Status.Conditions, platformv1alpha1.TenantConditionImagePolicyEnforced)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("NoPolicy"))
+			Expect(tenant.Status.Phase).To(Equal(platformv1alpha1.TenantPhaseError))
+		})
+
+		It("should report an unknown tier", func() {
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.Tier = "platinum"
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			cond := meta.FindStatusCondition(tenant.Status.Conditions, platformv1alpha1.TenantConditionTierResolved)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("UnknownTier"))
+			Expect(tenant.Status.Phase).To(Equal(platformv1alpha1.TenantPhaseError))
+		})
+
+		It("should report an invalid quantity instead of panicking", func() {
+			By("setting an unparsable quantity in the spec")
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.ResourceQuota.LimitsCPU = "lots"
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			cond := meta.FindStatusCondition(tenant.Status.Conditions, platformv1alpha1.TenantConditionQuotaReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("InvalidQuantity"))
+			Expect(cond.Message).To(ContainSubstring("spec.resourceQuota.limitsCPU"))
+		})
+
+		It("should translate spec.networkPolicy and prune stale policies", func() {
+			By("declaring an ingress rule in the spec")
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.NetworkPolicy = platformv1alpha1.NetworkPolicy{
+				PodSelector: map[string]string{"app": "api"},
+				Ingress: []platformv1alpha1.NetworkPolicyIngressRule{
+					{From: platformv1alpha1.NetworkPolicyPeer{Pod: map[string]string{"app": "web"}}},
+				},
+			}
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			policyKey := func(name string) types.NamespacedName {
+				return types.NamespacedName{Name: name, Namespace: "tenant-" + resourceName}
+			}
+			deny := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, policyKey("default-deny"), deny)).To(Succeed())
+			Expect(deny.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+			Expect(k8sClient.Get(ctx, policyKey("allow-dns"), &networkingv1.NetworkPolicy{})).To(Succeed())
+
+			allow := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, policyKey("tenant-allow"), allow)).To(Succeed())
+			Expect(allow.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("app", "api"))
+			Expect(allow.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+			Expect(allow.Spec.Ingress).To(HaveLen(1))
+			Expect(allow.Spec.Ingress[0].From[0].PodSelector.MatchLabels).To(HaveKeyWithValue("app", "web"))
+
+			By("removing the rules from the spec")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.NetworkPolicy = platformv1alpha1.NetworkPolicy{}
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, policyKey("tenant-allow"), &networkingv1.NetworkPolicy{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, policyKey("default-deny"), &networkingv1.NetworkPolicy{})).To(Succeed())
+		})
+
+		It("should translate ports, namespace and CIDR peers and require consent for tenant peers", func() {
+			By("allowing ingress from a namespace and egress to a CIDR and another tenant")
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.NetworkPolicy = platformv1alpha1.NetworkPolicy{
+				Ingress: []platformv1alpha1.NetworkPolicyIngressRule{{
+					From:  platformv1alpha1.NetworkPolicyPeer{Namespace: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"}},
+					Ports: []platformv1alpha1.NetworkPolicyPort{{Port: 8080}},
+				}},
+				Egress: []platformv1alpha1.NetworkPolicyEgressRule{
+					{
+						To:    platformv1alpha1.NetworkPolicyPeer{IPBlock: &platformv1alpha1.IPBlock{CIDR: "10.0.0.0/8"}},
+						Ports: []platformv1alpha1.NetworkPolicyPort{{Protocol: "TCP", Port: 5432}},
+					},
+					{To: platformv1alpha1.NetworkPolicyPeer{Tenant: "billing"}},
+				},
+			}
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			allowKey := types.NamespacedName{Name: "tenant-allow", Namespace: "tenant-" + resourceName}
+			allow := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, allowKey, allow)).To(Succeed())
+			Expect(allow.Spec.Ingress).To(HaveLen(1))
+			Expect(allow.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels).
+				To(HaveKeyWithValue("kubernetes.io/metadata.name", "ingress-nginx"))
+			Expect(allow.Spec.Ingress[0].Ports[0].Port.IntValue()).To(Equal(8080))
+			Expect(allow.Spec.Egress).To(HaveLen(1), "egress to a tenant without consent is skipped")
+			Expect(allow.Spec.Egress[0].To[0].IPBlock.CIDR).To(Equal("10.0.0.0/8"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			cond := meta.FindStatusCondition(tenant.Status.Conditions, platformv1alpha1.TenantConditionNetworkPolicyReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("TenantPeerNotConsented"))
+
+			By("provisioning the peer tenant without consent")
+			newTenant := func(name, namespace string) *platformv1alpha1.Tenant {
+				return &platformv1alpha1.Tenant{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+					Spec: platformv1alpha1.TenantSpec{
+						Owners:    []string{"owner@shieldx.io"},
+						Tier:      "silver",
+						Isolation: "namespace",
+					},
+				}
+			}
+			consent := platformv1alpha1.NetworkPolicy{
+				Ingress: []platformv1alpha1.NetworkPolicyIngressRule{
+					{From: platformv1alpha1.NetworkPolicyPeer{Tenant: resourceName}},
+				},
+			}
+			release := func(t *platformv1alpha1.Tenant) {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(t), t)).To(Succeed())
+				t.Finalizers = nil
+				Expect(k8sClient.Update(ctx, t)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, t))).To(Succeed())
+			}
+			peer := newTenant("billing", "default")
+			Expect(k8sClient.Create(ctx, peer)).To(Succeed())
+			DeferCleanup(release, peer)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(peer)})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("granting consent from a Tenant of the same name in another namespace")
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-other"}}
+			if err := k8sClient.Create(ctx, other); err != nil {
+				Expect(errors.IsAlreadyExists(err)).To(BeTrue())
+			}
+			impostor := newTenant("billing", other.Name)
+			impostor.Spec.NetworkPolicy = consent
+			Expect(k8sClient.Create(ctx, impostor)).To(Succeed())
+			DeferCleanup(release, impostor)
+			Expect(controllerReconciler.tenantsForPeer(ctx, impostor)).To(BeEmpty())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, allowKey, allow)).To(Succeed())
+			Expect(allow.Spec.Egress).To(HaveLen(1), "only the Tenant owning tenant-billing can consent")
+
+			By("granting consent from the peer tenant")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(peer), peer)).To(Succeed())
+			peer.Spec.NetworkPolicy = consent
+			Expect(k8sClient.Update(ctx, peer)).To(Succeed())
+			Expect(controllerReconciler.tenantsForPeer(ctx, peer)).To(ContainElement(reconcile.Request{NamespacedName: typeNamespacedName}))
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, allowKey, allow)).To(Succeed())
+			Expect(allow.Spec.Egress).To(HaveLen(2))
+			Expect(allow.Spec.Egress[1].To[0].NamespaceSelector.MatchLabels).To(Equal(map[string]string{
+				"tenant": "billing", "platform.shieldx.io/tenant-namespace": "default",
+			}))
+		})
+
+		It("should bind owners, members and viewers to the built-in roles", func() {
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.Members = []string{"group:developers", "sa:ci/deployer"}
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			bindingKey := func(name string) types.NamespacedName {
+				return types.NamespacedName{Name: name, Namespace: "tenant-" + resourceName}
+			}
+			admins := &rbacv1.RoleBinding{}
+			Expect(k8sClient.Get(ctx, bindingKey("tenant-admins"), admins)).To(Succeed())
+			Expect(admins.RoleRef.Name).To(Equal("admin"))
+			Expect(admins.Subjects).To(ConsistOf(rbacv1.Subject{
+				Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "owner@shieldx.io",
+			}))
+
+			members := &rbacv1.RoleBinding{}
+			Expect(k8sClient.Get(ctx, bindingKey("tenant-members"), members)).To(Succeed())
+			Expect(members.RoleRef.Name).To(Equal("edit"))
+			Expect(members.Subjects).To(ConsistOf(
+				rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "developers"},
+				rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "ci", Name: "deployer"},
+			))
+
+			err = k8sClient.Get(ctx, bindingKey("tenant-viewers"), &rbacv1.RoleBinding{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("removing the members")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.Members = nil
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, bindingKey("tenant-members"), &rbacv1.RoleBinding{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should refuse a namespace that belongs to another Tenant", func() {
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("creating a Tenant of the same name in another namespace")
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-other"}}
+			if err := k8sClient.Create(ctx, other); err != nil {
+				Expect(errors.IsAlreadyExists(err)).To(BeTrue())
+			}
+			hijackKey := types.NamespacedName{Name: resourceName, Namespace: other.Name}
+			hijack := &platformv1alpha1.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: hijackKey.Name, Namespace: hijackKey.Namespace},
+				Spec: platformv1alpha1.TenantSpec{
+					Owners:    []string{"mallory@example.com"},
+					Tier:      "silver",
+					Isolation: "namespace",
+				},
+			}
+			Expect(k8sClient.Create(ctx, hijack)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Get(ctx, hijackKey, hijack)).To(Succeed())
+				hijack.Finalizers = nil
+				Expect(k8sClient.Update(ctx, hijack)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, hijack))).To(Succeed())
+			})
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: hijackKey})
+			Expect(err).To(MatchError(ContainSubstring("belongs to Tenant default/" + resourceName)))
+
+			Expect(k8sClient.Get(ctx, hijackKey, hijack)).To(Succeed())
+			cond := meta.FindStatusCondition(hijack.Status.Conditions, platformv1alpha1.TenantConditionNamespaceReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("NamespaceConflict"))
+
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			Expect(ns.Labels).To(HaveKeyWithValue("platform.shieldx.io/tenant-namespace", "default"))
+			admins := &rbacv1.RoleBinding{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-admins", Namespace: "tenant-" + resourceName}, admins)).To(Succeed())
+			Expect(admins.Subjects).To(ConsistOf(HaveField("Name", "owner@shieldx.io")))
+		})
+
+		It("should keep the namespace when deletionPolicy is Retain", func() {
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			tenant.Spec.DeletionPolicy = platformv1alpha1.DeletionPolicyRetain
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("deleting the Tenant")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, tenant)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, typeNamespacedName, tenant)
+			Expect(errors.IsNotFound(err)).To(BeTrue(), "the finalizer is released right away")
+
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			Expect(ns.DeletionTimestamp.IsZero()).To(BeTrue())
+			Expect(ns.Labels).To(HaveKeyWithValue("platform.shieldx.io/retained", "true"))
+			Expect(ns.Labels).NotTo(HaveKey("platform.shieldx.io/tier"))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      "tenant-quota",
+				Namespace: "tenant-" + resourceName,
+			}, &corev1.ResourceQuota{})).To(Succeed())
+
+			By("recreating the Tenant in another namespace")
+			other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-other"}}
+			if err := k8sClient.Create(ctx, other); err != nil {
+				Expect(errors.IsAlreadyExists(err)).To(BeTrue())
+			}
+			adopterKey := types.NamespacedName{Name: resourceName, Namespace: other.Name}
+			adopter := &platformv1alpha1.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: adopterKey.Name, Namespace: adopterKey.Namespace},
+				Spec: platformv1alpha1.TenantSpec{
+					Owners:    []string{"owner@shieldx.io"},
+					Tier:      "silver",
+					Isolation: "namespace",
+				},
+			}
+			Expect(k8sClient.Create(ctx, adopter)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Get(ctx, adopterKey, adopter)).To(Succeed())
+				adopter.Finalizers = nil
+				Expect(k8sClient.Update(ctx, adopter)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, adopter))).To(Succeed())
+
+				// Hand the namespace back to the Tenant the other specs use.
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+				ns.Labels["platform.shieldx.io/tenant-namespace"] = "default"
+				Expect(k8sClient.Update(ctx, ns)).To(Succeed())
+			})
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: adopterKey})
+			Expect(err).To(MatchError(ContainSubstring("was retained from Tenant default/" + resourceName)))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			Expect(ns.Labels).To(HaveKeyWithValue("platform.shieldx.io/retained", "true"))
+			Expect(ns.Labels).To(HaveKeyWithValue("platform.shieldx.io/tenant-namespace", "default"))
+
+			By("annotating the namespace for adoption")
+			ns.Annotations = map[string]string{"platform.shieldx.io/adopt": "team-other/" + resourceName}
+			Expect(k8sClient.Update(ctx, ns)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: adopterKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			Expect(ns.Labels).NotTo(HaveKey("platform.shieldx.io/retained"))
+			Expect(ns.Labels).To(HaveKeyWithValue("platform.shieldx.io/tenant-namespace", "team-other"))
+			Expect(ns.Annotations).NotTo(HaveKey("platform.shieldx.io/adopt"))
+		})
+
+		It("should tear the tenant down through the finalizer", func() {
+			controllerReconciler := &TenantReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			tenant := &platformv1alpha1.Tenant{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			Expect(tenant.Finalizers).To(ContainElement("platform.shieldx.io/finalizer"))
+
+			By("deleting the Tenant")
+			Expect(k8sClient.Delete(ctx, tenant)).To(Succeed())
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-quota", Namespace: "tenant-" + resourceName}, &corev1.ResourceQuota{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-admins", Namespace: "tenant-" + resourceName}, &rbacv1.RoleBinding{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-" + resourceName}, ns)).To(Succeed())
+			Expect(ns.DeletionTimestamp.IsZero()).To(BeFalse())
+
+			By("waiting for the namespace before releasing the finalizer")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, tenant)).To(Succeed())
+			Expect(tenant.Finalizers).To(ContainElement("platform.shieldx.io/finalizer"))
+			Expect(tenant.Status.Phase).To(Equal(platformv1alpha1.TenantPhaseTerminating))
+			cond := meta.FindStatusCondition(tenant.Status.Conditions, platformv1alpha1.TenantConditionDeleting)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("WaitingForNamespace"))
+		})
 	})
 })
@@ -0,0 +1,574 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultFailureThreshold is how many consecutive scans a workload must fail
+// verification before an Enforce policy deletes or quarantines its pods.
+const defaultFailureThreshold = 3
+
+// defaultScanInterval is the time between periodic full scans.
+const defaultScanInterval = 10 * time.Minute
+
+// scanJitter delays each periodic scan by up to this fraction of the interval.
+const scanJitter = 0.1
+
+// Event reasons the signature scanner records on pods.
+const (
+	reasonImageVerificationFailed = "ImageVerificationFailed"
+	reasonUnverifiedPodDeleted    = "UnverifiedPodDeleted"
+)
+
+// loadScanSettings fills the scan settings left unset from SHIELDX_* variables.
+// SetupWithManager calls it before the manager starts, so the scanner and
+// PodImageReconciler only ever read the settings afterwards.
+func (r *TenantReconciler) loadScanSettings() {
+	log := logf.Log.WithName("tenant-signature-scanner")
+
+	// Periodic full scan interval. PodImageReconciler verifies new pods as they
+	// appear, so this only catches revoked signatures and policy changes.
+	// You can override via env, e.g. SHIELDX_SIGNATURE_SCAN_INTERVAL=2m
+	if v := strings.TrimSpace(strings.ToLower(getenv("SHIELDX_SIGNATURE_SCAN_INTERVAL", ""))); v != "" && r.ScanInterval == 0 {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			r.ScanInterval = d
+		} else {
+			log.Error(err, "invalid SHIELDX_SIGNATURE_SCAN_INTERVAL; using default", "value", v, "default", defaultScanInterval.String())
+		}
+	}
+	// SHIELDX_SIGNATURE_FAILURE_THRESHOLD=1 restores deletion on the first failed scan.
+	if v := getenv("SHIELDX_SIGNATURE_FAILURE_THRESHOLD", ""); v != "" && r.FailureThreshold == 0 {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			r.FailureThreshold = n
+		} else {
+			log.Error(err, "invalid SHIELDX_SIGNATURE_FAILURE_THRESHOLD; using default", "value", v, "default", defaultFailureThreshold)
+		}
+	}
+
+	r.Scan.fromEnv(log)
+	if r.Scan.Timeout <= 0 {
+		r.Scan.Timeout = r.scanInterval()
+	}
+	r.limitsOnce.Do(func() { r.limits = newRegistryLimiter(r.Scan) })
+}
+
+func (r *TenantReconciler) scanInterval() time.Duration {
+	if r.ScanInterval > 0 {
+		return r.ScanInterval
+	}
+	return defaultScanInterval
+}
+
+func (r *TenantReconciler) Start(ctx context.Context) error {
+	log := logf.Log.WithName("tenant-signature-scanner")
+	if r.Verifier == nil {
+		log.Info("no image verifier configured; periodic image signature enforcement disabled")
+		return nil
+	}
+
+	interval := r.scanInterval()
+	log.Info("starting periodic image signature enforcement", "interval", interval.String(),
+		"failureThreshold", r.failureThreshold(), "workers", r.Scan.workers(), "timeout", r.Scan.Timeout.String())
+	r.notify("[ShieldX] Started periodic image signature scans every " + interval.String())
+
+	// Run once at startup, then about every interval after the previous scan
+	// finished; the jitter spreads the load of several replicas or restarts.
+	wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.scanAndEnforcePodImages(ctx); err != nil {
+			log.Error(err, "periodic scan failed")
+		}
+	}, interval, scanJitter, true)
+	log.Info("stopping periodic image signature enforcement")
+	return nil
+}
+
+func (r *TenantReconciler) failureThreshold() int {
+	if r.FailureThreshold > 0 {
+		return r.FailureThreshold
+	}
+	return defaultFailureThreshold
+}
+
+func (r *TenantReconciler) scanAndEnforcePodImages(ctx context.Context) error {
+	log := logf.Log.WithName("tenant-signature-scanner")
+
+	if !r.scanning.CompareAndSwap(false, true) {
+		scansSkipped.Inc()
+		log.Info("previous scan still running; skipping")
+		return nil
+	}
+	defer r.scanning.Store(false)
+	defer func(start time.Time) { scanDuration.Observe(time.Since(start).Seconds()) }(time.Now())
+
+	var tenants platformv1alpha1.TenantList
+	if err := r.List(ctx, &tenants); err != nil {
+		return fmt.Errorf("list tenants: %w", err)
+	}
+
+	type tenantScan struct {
+		tenant    *platformv1alpha1.Tenant
+		namespace string
+		policies  imagepolicy.Set
+		findings  []*finding
+	}
+	var (
+		scans []*tenantScan
+		jobs  []scanJob
+	)
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if strings.TrimSpace(strings.ToLower(tenant.Spec.Isolation)) != "namespace" {
+			continue
+		}
+		tenantNS := tenantNamespaceName(tenant)
+
+		var pods corev1.PodList
+		if err := r.List(ctx, &pods, client.InNamespace(tenantNS)); err != nil {
+			// Namespace may not exist yet; don't fail the entire scan.
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, "failed to list pods in tenant namespace", "tenant", tenant.Name, "namespace", tenantNS)
+			continue
+		}
+
+		policies, err := r.ImagePolicies.Policies(ctx, tenantNS)
+		if err != nil {
+			log.Error(err, "failed to load image policies", "tenant", tenant.Name, "namespace", tenantNS)
+			continue
+		}
+
+		// Group pods by workload so replicas are reported and acted on once.
+		scan := &tenantScan{tenant: tenant, namespace: tenantNS, policies: policies}
+		scans = append(scans, scan)
+		cache := workloadCache{}
+		byWorkload := map[types.UID]*finding{}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			workload, err := r.resolveWorkload(ctx, pod, cache)
+			if err != nil {
+				log.Error(err, "failed to resolve workload; acting on the pod alone", "namespace", tenantNS, "pod", pod.Name)
+				workload = pod
+			}
+			f := byWorkload[workload.GetUID()]
+			if f == nil {
+				f = &finding{workload: workload}
+				byWorkload[workload.GetUID()] = f
+				scan.findings = append(scan.findings, f)
+			}
+			jobs = append(jobs, scanJob{pod: pod, policies: policies, finding: f})
+		}
+	}
+
+	// Only verification is bounded by the deadline, so acting on what was
+	// found still completes.
+	verifyCtx := ctx
+	if r.Scan.Timeout > 0 {
+		var cancel context.CancelFunc
+		verifyCtx, cancel = context.WithTimeout(ctx, r.Scan.Timeout)
+		defer cancel()
+	}
+	r.verifyPods(verifyCtx, jobs)
+	if err := verifyCtx.Err(); err != nil && ctx.Err() == nil {
+		log.Info("scan deadline exceeded; remaining images are verified on the next scan", "timeout", r.Scan.Timeout.String())
+	}
+
+	seen := map[types.UID]bool{}
+	for _, scan := range scans {
+		for _, f := range scan.findings {
+			seen[f.workload.GetUID()] = true
+			r.enforce(ctx, scan.tenant, f)
+		}
+		r.releaseQuarantined(verifyCtx, scan.tenant, scan.namespace, scan.policies)
+	}
+
+	// Forget workloads that are gone so their counters don't leak.
+	r.failures.retain(seen)
+	return nil
+}
+
+// finding is the verification outcome of the pods of one workload.
+type finding struct {
+	workload client.Object
+
+	mu sync.Mutex
+	// failing are the pods with an image that failed verification; image,
+	// decision and err describe the first one.
+	failing  []*corev1.Pod
+	image    string
+	decision imagepolicy.Decision
+	err      error
+	// unsure is set when an image could not be verified, e.g. during a
+	// registry or Rekor outage.
+	unsure bool
+	// podEvent marks the finding of a single pod verified on a pod event
+	// rather than by a full scan; it never counts as a failed scan.
+	podEvent bool
+}
+
+// String names the workload, e.g. "Deployment tenant-acme/api".
+func (f *finding) String() string {
+	return fmt.Sprintf("%s %s/%s", workloadKind(f.workload), f.workload.GetNamespace(), f.workload.GetName())
+}
+
+// record adds the outcome of one pod of the workload.
+func (f *finding) record(pod *corev1.Pod, image string, d imagepolicy.Decision, err error, unsure bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.unsure = f.unsure || unsure
+	if err == nil {
+		return
+	}
+	f.failing = append(f.failing, pod)
+	if f.err == nil {
+		f.image, f.decision, f.err = image, d, err
+	}
+}
+
+// verifyPod verifies the images of pod and records the first failing one in f.
+func (r *TenantReconciler) verifyPod(
+	ctx context.Context,
+	pod *corev1.Pod,
+	policies imagepolicy.Set,
+	f *finding,
+) {
+	log := logf.Log.WithName("tenant-signature-scanner").WithValues("namespace", pod.Namespace, "pod", pod.Name)
+
+	unsure := false
+	for _, img := range collectPodImages(&pod.Spec) {
+		d, err := policies.For(img)
+		if err == nil && d.Exempt {
+			continue
+		}
+		if err == nil {
+			_, err = r.verify(ctx, img, d.Policy)
+		}
+		if err == nil {
+			continue
+		}
+		if verifyimage.IsTransient(err) {
+			log.Info("could not verify image; retrying on the next scan", "image", img, "error", err.Error())
+			unsure = true
+			continue
+		}
+		// One failing image is enough to act on the pod; don't spam per container.
+		f.record(pod, img, d, err, unsure)
+		return
+	}
+	if !unsure {
+		r.releasePod(ctx, pod)
+	}
+	f.record(pod, "", imagepolicy.Decision{}, nil, unsure)
+}
+
+// enforce applies the mode of the policy the first failing image of a
+// workload falls under. Images that could not be verified neither count as
+// failures nor clear earlier ones. Under Enforce, each action raises the
+// number of failed scans needed for the next one, so a workload that keeps
+// recreating its pods is not hit in a hot loop.
+//
+// A pod event finding is a verdict on a new pod, so it acts at once on a
+// workload not acted on before. It leaves the failed scan count alone, and
+// once the workload was acted on its pod events wait for the full scans, so
+// replicas and recreated pods cannot drive the action in a hot loop.
+func (r *TenantReconciler) enforce(ctx context.Context, tenant *platformv1alpha1.Tenant, f *finding) {
+	uid := f.workload.GetUID()
+	if f.err == nil {
+		if !f.unsure && !f.podEvent {
+			r.failures.reset(uid)
+		}
+		return
+	}
+
+	var failures int
+	if f.podEvent {
+		failures = r.failures.count(uid)
+	} else {
+		failures = r.failures.inc(uid)
+	}
+	log := logf.Log.WithName("tenant-signature-scanner").WithValues(
+		"tenant", tenant.Name, "workload", f.String(), "pods", len(f.failing), "image", f.image,
+		"policy", f.decision.Policy.Name, "mode", f.decision.Mode, "failures", failures, "podEvent", f.podEvent)
+	switch f.decision.Mode {
+	case platformv1alpha1.ImagePolicyModeAudit:
+		log.Info("image failed signature verification (audit only)", "error", f.err.Error())
+
+	case platformv1alpha1.ImagePolicyModeWarn:
+		log.Info("image failed signature verification", "error", f.err.Error())
+		if r.failures.warn(uid) {
+			r.event(f.workload, corev1.EventTypeWarning, reasonImageVerificationFailed,
+				fmt.Sprintf("Image %s failed signature verification in %d pod(s): %v", f.image, len(f.failing), f.err))
+			r.notify(fmt.Sprintf(
+				"[ShieldX] Image signature verification failed (warn only)\nTenant: %s\nWorkload: %s\nPods: %d\nImage: %s\nError: %v",
+				tenant.Name, f, len(f.failing), f.image, f.err))
+		}
+
+	default:
+		if allQuarantined(f.failing) {
+			return
+		}
+		quarantine := f.decision.Action == platformv1alpha1.ImagePolicyActionQuarantine
+		required := r.failures.required(uid, r.failureThreshold())
+		// Quarantine stops the workload, so a pod event may always apply it;
+		// deleting pods again waits for consecutive failed scans.
+		wait := failures < required
+		if f.podEvent {
+			wait = !quarantine && r.failures.actions(uid) > 0
+		}
+		if wait {
+			action := strings.ToLower(f.decision.Action)
+			log.Info("image failed signature verification; acting on the workload if it keeps failing",
+				"required", required, "action", f.decision.Action)
+			if r.failures.warn(uid) {
+				r.event(f.workload, corev1.EventTypeWarning, reasonImageVerificationFailed,
+					fmt.Sprintf("Image %s failed signature verification; the pods are %sd after %d consecutive failed scans: %v",
+						f.image, action, required, f.err))
+			}
+			return
+		}
+
+		var (
+			reason, summary string
+			err             error
+		)
+		if quarantine {
+			reason, summary = reasonPodQuarantined, "Quarantined "+f.String()
+			err = r.quarantine(ctx, tenant, f.workload, f.failing)
+		} else {
+			reason, summary = reasonUnverifiedPodDeleted, fmt.Sprintf("Deleted %d pod(s) of %s", len(f.failing), f)
+			err = r.deletePods(ctx, f.failing)
+		}
+		if err != nil {
+			log.Error(err, "failed to act on non-compliant workload", "action", f.decision.Action)
+			return
+		}
+		if r.failures.acted(uid) > 1 {
+			log.Info("workload keeps running unverified images; policy action Quarantine stops it from recreating pods")
+		}
+
+		log.Info("acted on non-compliant workload (signature verification failed)", "action", f.decision.Action)
+		msg := fmt.Sprintf("%s: image %s failed signature verification: %v", summary, f.image, f.err)
+		r.event(f.workload, corev1.EventTypeWarning, reason, msg)
+		r.event(tenant, corev1.EventTypeWarning, reason, msg)
+		r.notify(fmt.Sprintf(
+			"[ShieldX] %s due to image signature verification failure\nTenant: %s\nImage: %s\nError: %v",
+			summary, tenant.Name, f.image, f.err))
+	}
+}
+
+// deletePods deletes the failing pods of a workload.
+func (r *TenantReconciler) deletePods(ctx context.Context, pods []*corev1.Pod) error {
+	for _, pod := range pods {
+		if err := r.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %q: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// releasePod lifts the quarantine of a pod whose images verify again.
+func (r *TenantReconciler) releasePod(ctx context.Context, pod *corev1.Pod) {
+	if !isQuarantined(pod) {
+		return
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	setQuarantined(pod, false)
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		logf.Log.WithName("tenant-signature-scanner").Error(err, "failed to release quarantined pod",
+			"namespace", pod.Namespace, "pod", pod.Name)
+		return
+	}
+	r.event(pod, corev1.EventTypeNormal, reasonQuarantineReleased, "Released from quarantine: its images pass signature verification")
+}
+
+func allQuarantined(pods []*corev1.Pod) bool {
+	for _, pod := range pods {
+		if !isQuarantined(pod) {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	// maxBackoffDoublings caps how often the failures needed before acting
+	// on the same workload again double.
+	maxBackoffDoublings = 5
+	// actionMemory is how long the tracker remembers acting on a workload
+	// whose pods are gone, so recreated pods don't restart the backoff.
+	actionMemory = time.Hour
+)
+
+// failureTracker counts the consecutive scans in which each workload failed
+// verification and how often the scanner acted on it.
+type failureTracker struct {
+	mu      sync.Mutex
+	entries map[types.UID]*failureEntry
+}
+
+type failureEntry struct {
+	failures   int
+	actions    int
+	lastAction time.Time
+	// warned is set once the failure was reported, until the next action.
+	warned bool
+}
+
+func (t *failureTracker) entry(uid types.UID) *failureEntry {
+	if t.entries == nil {
+		t.entries = map[types.UID]*failureEntry{}
+	}
+	e := t.entries[uid]
+	if e == nil {
+		e = &failureEntry{}
+		t.entries[uid] = e
+	}
+	return e
+}
+
+func (t *failureTracker) inc(uid types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(uid)
+	e.failures++
+	return e.failures
+}
+
+// count returns the failed scans of a workload without recording one.
+func (t *failureTracker) count(uid types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entries[uid]; e != nil {
+		return e.failures
+	}
+	return 0
+}
+
+// warn reports whether the failure of a workload still needs a warning, and
+// marks it warned. Full scans and pod events share it, so each is reported once.
+func (t *failureTracker) warn(uid types.UID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(uid)
+	if e.warned {
+		return false
+	}
+	e.warned = true
+	return true
+}
+
+// reset forgets a workload once it passes verification.
+func (t *failureTracker) reset(uid types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, uid)
+}
+
+// required returns the failed scans needed before acting on the workload:
+// threshold at first, doubling with every earlier action.
+func (t *failureTracker) required(uid types.UID, threshold int) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return threshold << min(t.entry(uid).actions, maxBackoffDoublings)
+}
+
+// actions returns how often the scanner acted on a workload.
+func (t *failureTracker) actions(uid types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entries[uid]; e != nil {
+		return e.actions
+	}
+	return 0
+}
+
+// acted records an action on the workload and returns how many were taken.
+func (t *failureTracker) acted(uid types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(uid)
+	e.failures = 0
+	e.warned = false
+	e.actions++
+	e.lastAction = time.Now()
+	return e.actions
+}
+
+// retain drops the entries of workloads not in seen, unless they were acted
+// on within actionMemory.
+func (t *failureTracker) retain(seen map[types.UID]bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for uid, e := range t.entries {
+		if !seen[uid] && time.Since(e.lastAction) > actionMemory {
+			delete(t.entries, uid)
+		}
+	}
+}
+
+func collectPodImages(spec *corev1.PodSpec) []string {
+	seen := make(map[string]struct{}, 8)
+	out := make([]string, 0, 8)
+
+	add := func(img string) {
+		img = strings.TrimSpace(img)
+		if img == "" {
+			return
+		}
+		if _, ok := seen[img]; ok {
+			return
+		}
+		seen[img] = struct{}{}
+		out = append(out, img)
+	}
+
+	for _, c := range spec.InitContainers {
+		add(c.Image)
+	}
+	for _, c := range spec.Containers {
+		add(c.Image)
+	}
+	return out
+}
+
+func getenv(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
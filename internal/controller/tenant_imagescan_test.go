@@ -0,0 +1,293 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage/fake"
+)
+
+var _ = Describe("Tenant image scanner", func() {
+	var (
+		c        client.Client
+		r        *TenantReconciler
+		verifier *fake.Verifier
+	)
+
+	pod := func(name, image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant-acme", UID: types.UID("uid-" + name)},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+		}
+	}
+	controllerRef := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: kind, Name: name, UID: types.UID("uid-" + name), Controller: ptr.To(true),
+		}}
+	}
+	exists := func(name string) bool {
+		err := c.Get(ctx, client.ObjectKey{Namespace: "tenant-acme", Name: name}, &corev1.Pod{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(platformv1alpha1.AddToScheme(s)).To(Succeed())
+		c = clientfake.NewClientBuilder().WithScheme(s).WithObjects(
+			&platformv1alpha1.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "acme"},
+				Spec:       platformv1alpha1.TenantSpec{Owners: []string{"alice"}, Isolation: "namespace"},
+			},
+			&platformv1alpha1.TenantImagePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "tenant-acme"},
+				Spec: platformv1alpha1.ImagePolicySpec{
+					Images: []string{"registry.example.com/audited/*"},
+					Mode:   platformv1alpha1.ImagePolicyModeAudit,
+				},
+			},
+			pod("unsigned", "registry.example.com/unsigned:1.0"),
+			pod("flaky", "registry.example.com/flaky:1.0"),
+			pod("audited", "registry.example.com/audited/app:1.0"),
+		).Build()
+		verifier = &fake.Verifier{Errors: map[string]error{
+			"registry.example.com/flaky:1.0": verifyimage.Transient(errors.New("registry unavailable")),
+		}}
+		r = &TenantReconciler{
+			Client:           c,
+			Verifier:         verifier,
+			ImagePolicies:    &imagepolicy.Resolver{Client: c},
+			FailureThreshold: 2,
+			Scan:             ScanOptions{RegistryQPS: -1},
+		}
+	})
+
+	It("should only delete pods whose images keep failing under an Enforce policy", func() {
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(exists("unsigned")).To(BeTrue(), "the first failure must not delete the pod")
+
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(exists("unsigned")).To(BeFalse())
+		Expect(exists("flaky")).To(BeTrue(), "outages must not count as failures")
+		Expect(exists("audited")).To(BeTrue(), "audit policies must not delete pods")
+	})
+
+	It("should treat a policy whose keys have all expired as a failure, not an outage", func() {
+		// The real verifier rejects such a policy before contacting a registry.
+		r.Verifier = verifyimage.NewCosignVerifier(nil)
+		for _, name := range []string{"unsigned", "flaky", "audited"} {
+			Expect(c.Delete(ctx, pod(name, ""))).To(Succeed())
+		}
+		expired := metav1.NewTime(time.Now().Add(-time.Hour))
+		Expect(c.Create(ctx, &platformv1alpha1.TenantImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "rotated", Namespace: "tenant-acme"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images: []string{"registry.example.com/rotated/*"},
+				Keys:   []platformv1alpha1.ImagePolicyKey{{Name: "2025", PublicKey: "pem", NotAfter: &expired}},
+			},
+		})).To(Succeed())
+		Expect(c.Create(ctx, pod("rotated", "registry.example.com/rotated/app:1.0"))).To(Succeed())
+
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(exists("rotated")).To(BeFalse())
+	})
+
+	It("should announce the periodic scan through Notify", func() {
+		var messages []string
+		r.Notify = func(message string) error {
+			messages = append(messages, message)
+			return nil
+		}
+		stopped, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(r.Start(stopped)).To(Succeed())
+		Expect(messages).To(ConsistOf(ContainSubstring("Started periodic image signature scans")))
+	})
+
+	It("should bound concurrent verifications per registry and skip overlapping scans", func() {
+		slow := &slowVerifier{inflight: map[string]int{}, peak: map[string]int{}}
+		r.Verifier = slow
+		r.Scan = ScanOptions{Workers: 8, RegistryConcurrency: 2, RegistryQPS: -1}
+		for i := range 6 {
+			Expect(c.Create(ctx, pod(fmt.Sprintf("a-%d", i), "a.example.com/app:1.0"))).To(Succeed())
+			Expect(c.Create(ctx, pod(fmt.Sprintf("b-%d", i), "b.example.com/app:1.0"))).To(Succeed())
+		}
+
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(slow.peak).To(HaveKeyWithValue("a.example.com", 2))
+		Expect(slow.peak).To(HaveKeyWithValue("b.example.com", 2))
+
+		calls := slow.calls
+		r.scanning.Store(true)
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(slow.calls).To(Equal(calls), "a scan must not start while another is running")
+	})
+
+	It("should act once per workload and back off when its pods are recreated", func() {
+		recorder := record.NewFakeRecorder(100)
+		r.Recorder = recorder
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme", UID: "uid-web"}}
+		replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-7c9d", Namespace: "tenant-acme", UID: "uid-web-7c9d", OwnerReferences: controllerRef("Deployment", "web"),
+		}}
+		Expect(c.Create(ctx, deployment)).To(Succeed())
+		Expect(c.Create(ctx, replicaSet)).To(Succeed())
+		replica := func(name string) {
+			p := pod(name, "registry.example.com/web:1.0")
+			p.OwnerReferences = controllerRef("ReplicaSet", "web-7c9d")
+			Expect(c.Create(ctx, p)).To(Succeed())
+		}
+		deleted := func() int {
+			n := 0
+			for {
+				select {
+				case e := <-recorder.Events:
+					if strings.Contains(e, reasonUnverifiedPodDeleted) && strings.Contains(e, "Deployment tenant-acme/web") {
+						n++
+					}
+				default:
+					return n
+				}
+			}
+		}
+		replica("web-7c9d-a")
+		replica("web-7c9d-b")
+
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(exists("web-7c9d-a")).To(BeFalse())
+		Expect(exists("web-7c9d-b")).To(BeFalse())
+		// One Event on the Deployment and one on the Tenant, not one per replica.
+		Expect(deleted()).To(Equal(2))
+
+		By("recreating the replica")
+		replica("web-7c9d-c")
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(exists("web-7c9d-c")).To(BeTrue(), "a second action needs twice the failed scans")
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(exists("web-7c9d-c")).To(BeFalse())
+	})
+
+	It("should quarantine a failing workload and release it once its image verifies", func() {
+		template := corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/quarantined/api:1.0"}}},
+		}
+		selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "tenant-acme", UID: "uid-api"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3), Selector: selector, Template: template},
+		}
+		replicaSet := &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-5d8f", Namespace: "tenant-acme", OwnerReferences: controllerRef("Deployment", "api")},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: ptr.To[int32](3), Selector: selector, Template: template},
+		}
+		p := pod("api-5d8f-x2k9", "registry.example.com/quarantined/api:1.0")
+		p.OwnerReferences = controllerRef("ReplicaSet", "api-5d8f")
+		policy := &platformv1alpha1.TenantImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "quarantine", Namespace: "tenant-acme"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images: []string{"registry.example.com/quarantined/*"},
+				Action: platformv1alpha1.ImagePolicyActionQuarantine,
+			},
+		}
+		for _, obj := range []client.Object{deployment, replicaSet, p, policy} {
+			Expect(c.Create(ctx, obj)).To(Succeed())
+		}
+
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(p), p)).To(Succeed())
+		Expect(p.Labels).To(HaveKeyWithValue(quarantineLabel, "true"), "quarantined pods are not deleted")
+		var isolation networkingv1.NetworkPolicy
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "tenant-acme", Name: quarantinePolicyName}, &isolation)).To(Succeed())
+		Expect(isolation.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue(quarantineLabel, "true"))
+		Expect(isolation.Spec.Ingress).To(BeEmpty())
+		Expect(isolation.Spec.Egress).To(BeEmpty())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(BeZero())
+		Expect(deployment.Annotations).To(HaveKeyWithValue(quarantinedReplicasAnnotation, "3"))
+
+		By("fixing the image")
+		deployment.Spec.Template.Spec.Containers[0].Image = "registry.example.com/quarantined/api:1.1"
+		Expect(c.Update(ctx, deployment)).To(Succeed())
+		verifier.Digests = map[string]string{"registry.example.com/quarantined/api:1.1": ""}
+		Expect(r.scanAndEnforcePodImages(ctx)).To(Succeed())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(BeEquivalentTo(3))
+		Expect(deployment.Labels).NotTo(HaveKey(quarantineLabel))
+		Expect(deployment.Annotations).NotTo(HaveKey(quarantinedReplicasAnnotation))
+	})
+})
+
+// slowVerifier accepts every image after a delay and records the peak
+// number of verifications in flight per registry.
+type slowVerifier struct {
+	mu       sync.Mutex
+	calls    int
+	inflight map[string]int
+	peak     map[string]int
+}
+
+func (v *slowVerifier) Verify(_ context.Context, image string, _ verifyimage.Policy) (verifyimage.Result, error) {
+	registry := registryOf(image)
+	v.mu.Lock()
+	v.calls++
+	v.inflight[registry]++
+	v.peak[registry] = max(v.peak[registry], v.inflight[registry])
+	v.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	v.mu.Lock()
+	v.inflight[registry]--
+	v.mu.Unlock()
+	return verifyimage.Result{Digest: image}, nil
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// componentLabel tells apart the kinds of objects the controller generates
+	// in a tenant namespace, so each kind can be pruned independently.
+	componentLabel         = "platform.shieldx.io/component"
+	componentNetworkPolicy = "network-policy"
+
+	defaultDenyPolicyName = "default-deny"
+	allowDNSPolicyName    = "allow-dns"
+	tenantAllowPolicyName = "tenant-allow"
+)
+
+// generatedPolicy is a NetworkPolicy the controller keeps in the tenant namespace.
+type generatedPolicy struct {
+	Name string
+	Spec networkingv1.NetworkPolicySpec
+}
+
+// tenantPeers resolves the tenants named by "tenant" peers of network rules.
+type tenantPeers struct {
+	// owners maps a peer name to the namespace of the Tenant that owns its
+	// tenant namespace; peers without such a Tenant are left out.
+	owners map[string]string
+	// blocked lists egress peers that are unknown or have not consented.
+	blocked []string
+}
+
+// desiredNetworkPolicies returns every NetworkPolicy the tenant namespace should contain:
+// a default deny, a DNS egress allowance and the allow rules from spec.networkPolicy.
+// Rules to unknown tenants and egress rules to blocked ones are left out. Allowances
+// never select quarantined pods, which default-deny then cuts off entirely.
+func desiredNetworkPolicies(tenant *platformv1alpha1.Tenant, peers tenantPeers) []generatedPolicy {
+	policies := []generatedPolicy{
+		{
+			Name: defaultDenyPolicyName,
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: []networkingv1.PolicyType{
+					networkingv1.PolicyTypeIngress,
+					networkingv1.PolicyTypeEgress,
+				},
+			},
+		},
+		{
+			Name: allowDNSPolicyName,
+			Spec: dnsEgressPolicySpec(),
+		},
+	}
+
+	if spec, ok := tenantAllowPolicySpec(tenant.Spec.NetworkPolicy, peers); ok {
+		policies = append(policies, generatedPolicy{Name: tenantAllowPolicyName, Spec: spec})
+	}
+	return policies
+}
+
+// dnsEgressPolicySpec lets every pod resolve names through the cluster DNS
+// in kube-system, which default-deny would otherwise block.
+func dnsEgressPolicySpec() networkingv1.NetworkPolicySpec {
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	port := intstr.FromInt32(53)
+
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: notQuarantined(metav1.LabelSelector{}),
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			{
+				To: []networkingv1.NetworkPolicyPeer{
+					{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"},
+						},
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+						},
+					},
+				},
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &udp, Port: &port},
+					{Protocol: &tcp, Port: &port},
+				},
+			},
+		},
+	}
+}
+
+// tenantAllowPolicySpec translates the Tenant's simplified NetworkPolicy into
+// a Kubernetes NetworkPolicy. It returns false when the spec declares no rules.
+func tenantAllowPolicySpec(np platformv1alpha1.NetworkPolicy, peers tenantPeers) (networkingv1.NetworkPolicySpec, bool) {
+	if len(np.PolicyTypes) == 0 && len(np.Ingress) == 0 && len(np.Egress) == 0 {
+		return networkingv1.NetworkPolicySpec{}, false
+	}
+
+	wantIngress, wantEgress := len(np.Ingress) > 0, len(np.Egress) > 0
+	for _, t := range np.PolicyTypes {
+		switch networkingv1.PolicyType(t) {
+		case networkingv1.PolicyTypeIngress:
+			wantIngress = true
+		case networkingv1.PolicyTypeEgress:
+			wantEgress = true
+		}
+	}
+
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: notQuarantined(metav1.LabelSelector{MatchLabels: np.PodSelector}),
+	}
+	if wantIngress {
+		spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+	}
+	if wantEgress {
+		spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+	}
+
+	for _, in := range np.Ingress {
+		from, ok := networkPeer(in.From, peers.owners)
+		if !ok {
+			continue
+		}
+		spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  []networkingv1.NetworkPolicyPeer{from},
+			Ports: networkPorts(in.Ports),
+		})
+	}
+	for _, eg := range np.Egress {
+		to, ok := networkPeer(eg.To, peers.owners)
+		if !ok || eg.To.Tenant != "" && slices.Contains(peers.blocked, eg.To.Tenant) {
+			continue
+		}
+		spec.Egress = append(spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{to},
+			Ports: networkPorts(eg.Ports),
+		})
+	}
+	return spec, true
+}
+
+// notQuarantined narrows sel to pods without the quarantine label.
+func notQuarantined(sel metav1.LabelSelector) metav1.LabelSelector {
+	sel.MatchExpressions = append(sel.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      quarantineLabel,
+		Operator: metav1.LabelSelectorOpDoesNotExist,
+	})
+	return sel
+}
+
+// networkPeer translates a simplified peer. Without a namespace or tenant
+// selector, pods are selected in the tenant namespace; an empty pod selector
+// means every pod. A tenant peer selects the namespace labeled for the Tenant
+// owners resolves it to, and is dropped (false) when it resolves to none.
+func networkPeer(peer platformv1alpha1.NetworkPolicyPeer, owners map[string]string) (networkingv1.NetworkPolicyPeer, bool) {
+	if peer.IPBlock != nil {
+		return networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: peer.IPBlock.CIDR, Except: peer.IPBlock.Except},
+		}, true
+	}
+
+	var out networkingv1.NetworkPolicyPeer
+	switch {
+	case peer.Tenant != "":
+		owner, ok := owners[peer.Tenant]
+		if !ok {
+			return out, false
+		}
+		out.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{
+			tenantNameLabel:      peer.Tenant,
+			tenantNamespaceLabel: owner,
+		}}
+	case peer.Namespace != nil:
+		out.NamespaceSelector = &metav1.LabelSelector{MatchLabels: peer.Namespace}
+	}
+	if out.NamespaceSelector == nil || len(peer.Pod) > 0 {
+		out.PodSelector = &metav1.LabelSelector{MatchLabels: peer.Pod}
+	}
+	return out, true
+}
+
+// networkPorts translates rule ports; protocol defaults to TCP.
+func networkPorts(ports []platformv1alpha1.NetworkPolicyPort) []networkingv1.NetworkPolicyPort {
+	if len(ports) == 0 {
+		return nil
+	}
+	out := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, p := range ports {
+		protocol := corev1.ProtocolTCP
+		if p.Protocol != "" {
+			protocol = corev1.Protocol(p.Protocol)
+		}
+		port := intstr.FromInt32(p.Port)
+		np := networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+		if p.EndPort > p.Port {
+			endPort := p.EndPort
+			np.EndPort = &endPort
+		}
+		out = append(out, np)
+	}
+	return out
+}
+
+// resolveTenantPeers resolves every tenant named in the network rules to the
+// Tenant that owns its namespace, and blocks egress to those that have not
+// agreed to receive it. A tenant consents by declaring an ingress rule from
+// this tenant. Tenants are namespaced, so peers are matched on name and
+// namespace: a Tenant of the same name elsewhere cannot grant consent.
+func (r *TenantReconciler) resolveTenantPeers(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) (tenantPeers, error) {
+	np := tenant.Spec.NetworkPolicy
+	peers := tenantPeers{owners: map[string]string{}}
+	var names []string
+	for _, in := range np.Ingress {
+		names = append(names, in.From.Tenant)
+	}
+	for _, eg := range np.Egress {
+		names = append(names, eg.To.Tenant)
+	}
+	for _, name := range names {
+		if _, done := peers.owners[name]; done || name == "" {
+			continue
+		}
+		owner, err := r.tenantOwner(ctx, name)
+		if err != nil {
+			return tenantPeers{}, err
+		}
+		if owner != "" {
+			peers.owners[name] = owner
+		}
+	}
+
+	for _, eg := range np.Egress {
+		name := eg.To.Tenant
+		if name == "" || name == tenant.Name || slices.Contains(peers.blocked, name) {
+			continue
+		}
+		consented := false
+		if owner, ok := peers.owners[name]; ok {
+			var peer platformv1alpha1.Tenant
+			err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: owner}, &peer)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return tenantPeers{}, fmt.Errorf("failed to get tenant %s/%s: %w", owner, name, err)
+			}
+			consented = err == nil && acceptsTenant(&peer, tenant.Name)
+		}
+		if !consented {
+			peers.blocked = append(peers.blocked, name)
+		}
+	}
+	return peers, nil
+}
+
+// tenantOwner returns the namespace of the Tenant named name that owns the
+// tenant namespace, or "" while the namespace does not exist, is retained or
+// is labeled for no such Tenant.
+func (r *TenantReconciler) tenantOwner(ctx context.Context, name string) (string, error) {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: tenantNamespacePrefix + name}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get namespace of tenant %q: %w", name, err)
+	}
+	if ns.Labels[tenantNameLabel] != name || ns.Labels[retainedLabel] == "true" {
+		return "", nil
+	}
+	return ns.Labels[tenantNamespaceLabel], nil
+}
+
+// acceptsTenant reports whether tenant has an ingress rule from the named
+// tenant. The requesting tenant owns its namespace, as provisioning stops
+// before network policies otherwise, so the name identifies it.
+func acceptsTenant(tenant *platformv1alpha1.Tenant, from string) bool {
+	for _, in := range tenant.Spec.NetworkPolicy.Ingress {
+		if in.From.Tenant == from {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantPeerMessage explains which egress rules were skipped for lack of consent.
+func tenantPeerMessage(tenant *platformv1alpha1.Tenant, blocked []string) string {
+	return fmt.Sprintf("egress to tenant(s) %s skipped: they must exist and have an ingress rule from tenant %q",
+		strings.Join(blocked, ", "), tenant.Name)
+}
+
+// tenantsForPeer re-queues every other Tenant with a rule naming the Tenant
+// that changed, so granting or revoking consent takes effect. Changes to a
+// Tenant that does not own its namespace are ignored.
+func (r *TenantReconciler) tenantsForPeer(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+	owner, err := r.tenantOwner(ctx, obj.GetName())
+	if err != nil {
+		log.Error(err, "failed to resolve tenant", "peer", obj.GetName())
+		return nil
+	}
+	if owner != obj.GetNamespace() {
+		return nil
+	}
+
+	var tenants platformv1alpha1.TenantList
+	if err := r.List(ctx, &tenants); err != nil {
+		log.Error(err, "failed to list tenants", "peer", obj.GetName())
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for _, t := range tenants.Items {
+		if t.Name == obj.GetName() && t.Namespace == obj.GetNamespace() {
+			continue
+		}
+		if namesTenant(t.Spec.NetworkPolicy, obj.GetName()) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: t.Name, Namespace: t.Namespace}})
+		}
+	}
+	return reqs
+}
+
+// namesTenant reports whether a rule of np has the named tenant as its peer.
+func namesTenant(np platformv1alpha1.NetworkPolicy, name string) bool {
+	for _, in := range np.Ingress {
+		if in.From.Tenant == name {
+			return true
+		}
+	}
+	for _, eg := range np.Egress {
+		if eg.To.Tenant == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// quarantineLabel marks pods and workloads the image scanner quarantined.
+	// Generated allow rules skip labeled pods (see notQuarantined).
+	quarantineLabel = "platform.shieldx.io/quarantined"
+	// quarantinedReplicasAnnotation keeps the replicas a quarantined workload
+	// had, so releasing it scales it back up.
+	quarantinedReplicasAnnotation = "platform.shieldx.io/quarantined-replicas"
+
+	// The quarantine NetworkPolicy has its own component so that
+	// ensureNetworkPolicies does not prune it.
+	componentQuarantine  = "quarantine"
+	quarantinePolicyName = "quarantine"
+
+	reasonPodQuarantined     = "PodQuarantined"
+	reasonQuarantineReleased = "QuarantineReleased"
+)
+
+// quarantine cuts the failing pods of a workload off the network and stops
+// the workload from creating more: Deployments, StatefulSets and ReplicaSets
+// are scaled to zero, Jobs and CronJobs suspended. DaemonSets cannot be
+// stopped, so only their pods are isolated.
+func (r *TenantReconciler) quarantine(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	workload client.Object,
+	pods []*corev1.Pod,
+) error {
+
+	if err := r.ensureQuarantinePolicy(ctx, tenant); err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if isQuarantined(pod) {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		setQuarantined(pod, true)
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return fmt.Errorf("failed to label pod %q: %w", pod.Name, err)
+		}
+	}
+
+	replicas, suspend := workloadReplicas(workload), workloadSuspend(workload)
+	if replicas == nil && suspend == nil {
+		return nil
+	}
+	patch := client.MergeFrom(workload.DeepCopyObject().(client.Object))
+	if replicas != nil {
+		annotations := workload.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		// Keep the original count when the workload was scaled up again while quarantined.
+		if _, ok := annotations[quarantinedReplicasAnnotation]; !ok {
+			annotations[quarantinedReplicasAnnotation] = strconv.Itoa(int(ptr.Deref(*replicas, 1)))
+		}
+		workload.SetAnnotations(annotations)
+		*replicas = ptr.To[int32](0)
+	} else {
+		*suspend = ptr.To(true)
+	}
+	setQuarantined(workload, true)
+	if err := r.Patch(ctx, workload, patch); err != nil {
+		return fmt.Errorf("failed to stop %s %q: %w", workloadKind(workload), workload.GetName(), err)
+	}
+	return nil
+}
+
+// ensureQuarantinePolicy applies a NetworkPolicy that selects quarantined pods
+// and allows no traffic at all.
+func (r *TenantReconciler) ensureQuarantinePolicy(ctx context.Context, tenant *platformv1alpha1.Tenant) error {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      quarantinePolicyName,
+			Namespace: tenantNamespaceName(tenant),
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(
+		ctx,
+		r.Client,
+		policy,
+		func() error {
+			setTenantLabels(policy, tenant)
+			policy.Labels[componentLabel] = componentQuarantine
+			policy.Spec = networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{quarantineLabel: "true"}},
+				PolicyTypes: []networkingv1.PolicyType{
+					networkingv1.PolicyTypeIngress,
+					networkingv1.PolicyTypeEgress,
+				},
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to apply NetworkPolicy %q: %w", quarantinePolicyName, err)
+	}
+	return nil
+}
+
+// releaseQuarantined scales up or resumes the quarantined workloads in
+// namespace once every image of their pod template verifies, e.g. after the
+// image was re-signed or replaced with a signed one.
+func (r *TenantReconciler) releaseQuarantined(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	namespace string,
+	policies imagepolicy.Set,
+) {
+	log := logf.Log.WithName("tenant-signature-scanner").WithValues("tenant", tenant.Name, "namespace", namespace)
+
+	for _, list := range []client.ObjectList{
+		&appsv1.DeploymentList{},
+		&appsv1.StatefulSetList{},
+		&appsv1.ReplicaSetList{},
+		&batchv1.JobList{},
+		&batchv1.CronJobList{},
+	} {
+		if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels{quarantineLabel: "true"}); err != nil {
+			log.Error(err, "failed to list quarantined workloads")
+			continue
+		}
+		_ = meta.EachListItem(list, func(o runtime.Object) error {
+			workload := o.(client.Object)
+			kind := workloadKind(workload)
+			if !r.imagesVerify(ctx, collectPodImages(workloadPodSpec(workload)), policies) {
+				return nil
+			}
+
+			patch := client.MergeFrom(workload.DeepCopyObject().(client.Object))
+			annotations := workload.GetAnnotations()
+			if replicas := workloadReplicas(workload); replicas != nil {
+				if n, err := strconv.Atoi(annotations[quarantinedReplicasAnnotation]); err == nil {
+					*replicas = ptr.To(int32(n))
+				}
+			}
+			if suspend := workloadSuspend(workload); suspend != nil {
+				*suspend = ptr.To(false)
+			}
+			delete(annotations, quarantinedReplicasAnnotation)
+			workload.SetAnnotations(annotations)
+			setQuarantined(workload, false)
+			if err := r.Patch(ctx, workload, patch); err != nil {
+				log.Error(err, "failed to release quarantined workload", "kind", kind, "name", workload.GetName())
+				return nil
+			}
+
+			log.Info("released quarantined workload (images verified)", "kind", kind, "name", workload.GetName())
+			msg := fmt.Sprintf("Released %s %s/%s from quarantine: its images pass signature verification",
+				kind, namespace, workload.GetName())
+			r.event(workload, corev1.EventTypeNormal, reasonQuarantineReleased, msg)
+			r.notify("[ShieldX] " + msg)
+			return nil
+		})
+	}
+}
+
+// imagesVerify reports whether every image is exempt or passes verification.
+func (r *TenantReconciler) imagesVerify(ctx context.Context, images []string, policies imagepolicy.Set) bool {
+	for _, img := range images {
+		d, err := policies.For(img)
+		if err == nil && d.Exempt {
+			continue
+		}
+		if err == nil {
+			_, err = r.verify(ctx, img, d.Policy)
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isQuarantined(obj metav1.Object) bool {
+	return obj.GetLabels()[quarantineLabel] == "true"
+}
+
+func setQuarantined(obj metav1.Object, quarantined bool) {
+	labels := obj.GetLabels()
+	if !quarantined {
+		delete(labels, quarantineLabel)
+		obj.SetLabels(labels)
+		return
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[quarantineLabel] = "true"
+	obj.SetLabels(labels)
+}
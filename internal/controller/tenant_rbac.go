@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/identity"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// tenantBinding is a RoleBinding granting a built-in ClusterRole to a list of identities.
+type tenantBinding struct {
+	Name        string
+	ClusterRole string
+	Field       string
+	Entries     []string
+}
+
+// tenantBindings lists the RoleBindings derived from owners, members and viewers.
+func tenantBindings(tenant *platformv1alpha1.Tenant) []tenantBinding {
+	return []tenantBinding{
+		{Name: "tenant-admins", ClusterRole: "admin", Field: "spec.owners", Entries: tenant.Spec.Owners},
+		{Name: "tenant-members", ClusterRole: "edit", Field: "spec.members", Entries: tenant.Spec.Members},
+		{Name: "tenant-viewers", ClusterRole: "view", Field: "spec.viewers", Entries: tenant.Spec.Viewers},
+	}
+}
+
+// bindingSubjects parses every entry of a binding and describes each invalid one.
+func bindingSubjects(b tenantBinding, namespace string) ([]rbacv1.Subject, []string) {
+	var subjects []rbacv1.Subject
+	var problems []string
+	for i, entry := range b.Entries {
+		s, err := identity.ParseSubject(entry, namespace)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s[%d]: %v", b.Field, i, err))
+			continue
+		}
+		subjects = append(subjects, s)
+	}
+	return subjects, problems
+}
+
+// ensureRoleBinding keeps one tenant RoleBinding in sync, deleting it when
+// it has no subjects. A binding whose roleRef was changed is recreated,
+// since roleRef is immutable.
+func (r *TenantReconciler) ensureRoleBinding(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	b tenantBinding,
+	subjects []rbacv1.Subject,
+) error {
+
+	rb := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name,
+			Namespace: tenantNamespaceName(tenant),
+		},
+	}
+	roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: b.ClusterRole}
+
+	var existing rbacv1.RoleBinding
+	err := r.Get(ctx, client.ObjectKeyFromObject(rb), &existing)
+	switch {
+	case apierrors.IsNotFound(err):
+		if len(subjects) == 0 {
+			return nil
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get RoleBinding %q: %w", b.Name, err)
+	case len(subjects) == 0 || existing.RoleRef != roleRef:
+		if err := r.Delete(ctx, &existing); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete RoleBinding %q: %w", b.Name, err)
+		}
+		if len(subjects) == 0 {
+			return nil
+		}
+	}
+
+	_, err = controllerutil.CreateOrUpdate(
+		ctx,
+		r.Client,
+		rb,
+		func() error {
+			setTenantLabels(rb, tenant)
+			rb.RoleRef = roleRef
+			rb.Subjects = subjects
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to apply RoleBinding %q: %w", b.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	scanDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "shieldx_image_scan_duration_seconds",
+		Help:    "Duration of periodic image signature scans.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+	})
+	scanQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "shieldx_image_scan_queue_depth",
+		Help: "Pods of the running image scan waiting for a verification worker.",
+	})
+	scansSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "shieldx_image_scans_skipped_total",
+		Help: "Image scans skipped because the previous one was still running.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(scanDuration, scanQueueDepth, scansSkipped)
+}
+
+// Scan defaults; see ScanOptions.
+const (
+	defaultScanWorkers         = 8
+	defaultRegistryConcurrency = 4
+	defaultRegistryQPS         = 5
+)
+
+// ScanOptions bounds the work of the periodic image scan. Zero values use the defaults.
+type ScanOptions struct {
+	// Workers is how many pods are verified at once; defaults to 8.
+	Workers int
+	// RegistryConcurrency bounds the verifications in flight per registry; defaults to 4.
+	RegistryConcurrency int
+	// RegistryQPS bounds the verifications started per second per registry;
+	// defaults to 5, negative means unlimited.
+	RegistryQPS float64
+	// Timeout is the deadline for verifying all images of one scan; images
+	// not verified in time are retried on the next scan. SetupWithManager
+	// defaults it to the scan interval.
+	Timeout time.Duration
+}
+
+// fromEnv fills unset options from SHIELDX_SCAN_* variables.
+func (o *ScanOptions) fromEnv(log logr.Logger) {
+	parse := func(key string, set func(string) error) {
+		if v := getenv(key, ""); v != "" {
+			if err := set(v); err != nil {
+				log.Error(err, "invalid "+key+"; using default", "value", v)
+			}
+		}
+	}
+	if o.Workers == 0 {
+		parse("SHIELDX_SCAN_WORKERS", func(v string) (err error) { o.Workers, err = strconv.Atoi(v); return })
+	}
+	if o.RegistryConcurrency == 0 {
+		parse("SHIELDX_SCAN_REGISTRY_CONCURRENCY", func(v string) (err error) {
+			o.RegistryConcurrency, err = strconv.Atoi(v)
+			return
+		})
+	}
+	if o.RegistryQPS == 0 {
+		parse("SHIELDX_SCAN_REGISTRY_QPS", func(v string) (err error) {
+			o.RegistryQPS, err = strconv.ParseFloat(v, 64)
+			return
+		})
+	}
+	if o.Timeout == 0 {
+		parse("SHIELDX_SCAN_TIMEOUT", func(v string) (err error) { o.Timeout, err = time.ParseDuration(v); return })
+	}
+}
+
+func (o ScanOptions) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return defaultScanWorkers
+}
+
+// scanJob verifies one pod and records the outcome in the finding of its workload.
+type scanJob struct {
+	pod      *corev1.Pod
+	policies imagepolicy.Set
+	finding  *finding
+}
+
+// verifyPods runs jobs on a bounded pool of workers. Jobs still queued
+// when ctx is done are not verified and leave their workload unsure.
+func (r *TenantReconciler) verifyPods(ctx context.Context, jobs []scanJob) {
+	queue := make(chan scanJob)
+	scanQueueDepth.Set(float64(len(jobs)))
+	defer scanQueueDepth.Set(0)
+
+	var wg sync.WaitGroup
+	for range min(r.Scan.workers(), len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				scanQueueDepth.Dec()
+				if ctx.Err() != nil {
+					job.finding.record(nil, "", imagepolicy.Decision{}, nil, true)
+					continue
+				}
+				r.verifyPod(ctx, job.pod, job.policies, job.finding)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// verify checks one image within the limits of its registry. Waiting past
+// the scan deadline is reported as a transient failure. The limiter is built
+// by SetupWithManager, or on first use when the reconciler is not set up.
+func (r *TenantReconciler) verify(ctx context.Context, image string, policy verifyimage.Policy) (verifyimage.Result, error) {
+	r.limitsOnce.Do(func() { r.limits = newRegistryLimiter(r.Scan) })
+	release, err := r.limits.acquire(ctx, image)
+	if err != nil {
+		return verifyimage.Result{}, verifyimage.Transient(err)
+	}
+	defer release()
+	return r.Verifier.Verify(ctx, image, policy)
+}
+
+// registryLimiter bounds the concurrency and rate of verifications per
+// registry, so one slow or strict registry does not hold up the others.
+type registryLimiter struct {
+	concurrency int
+	qps         rate.Limit
+
+	mu         sync.Mutex
+	registries map[string]*registryLimit
+}
+
+type registryLimit struct {
+	slots chan struct{}
+	rate  *rate.Limiter
+}
+
+func newRegistryLimiter(o ScanOptions) *registryLimiter {
+	l := &registryLimiter{
+		concurrency: defaultRegistryConcurrency,
+		qps:         defaultRegistryQPS,
+		registries:  map[string]*registryLimit{},
+	}
+	if o.RegistryConcurrency > 0 {
+		l.concurrency = o.RegistryConcurrency
+	}
+	switch {
+	case o.RegistryQPS > 0:
+		l.qps = rate.Limit(o.RegistryQPS)
+	case o.RegistryQPS < 0:
+		l.qps = rate.Inf
+	}
+	return l
+}
+
+// acquire waits for a slot of the registry of image and returns the function
+// that frees it.
+func (l *registryLimiter) acquire(ctx context.Context, image string) (func(), error) {
+	lim := l.registry(registryOf(image))
+	if err := lim.rate.Wait(ctx); err != nil {
+		return nil, err
+	}
+	select {
+	case lim.slots <- struct{}{}:
+		return func() { <-lim.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *registryLimiter) registry(host string) *registryLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lim, ok := l.registries[host]
+	if !ok {
+		lim = &registryLimit{
+			slots: make(chan struct{}, l.concurrency),
+			rate:  rate.NewLimiter(l.qps, l.concurrency),
+		}
+		l.registries[host] = lim
+	}
+	return lim
+}
+
+// registryOf returns the registry host of image, e.g. index.docker.io for "nginx".
+func registryOf(image string) string {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return ""
+	}
+	return ref.Context().RegistryStr()
+}
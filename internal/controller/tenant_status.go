@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reasonReconcileError is used when a child resource could not be applied
+// because of an API error; the request is retried with backoff.
+const reasonReconcileError = "ReconcileError"
+
+// readinessConditions must all be True for a Tenant to be Ready.
+// ImagePolicyEnforced only counts for Tenants that enforce image signatures.
+func readinessConditions(tenant *platformv1alpha1.Tenant) []string {
+	conditions := []string{
+		platformv1alpha1.TenantConditionTierResolved,
+		platformv1alpha1.TenantConditionNamespaceReady,
+		platformv1alpha1.TenantConditionQuotaReady,
+		platformv1alpha1.TenantConditionNetworkPolicyReady,
+		platformv1alpha1.TenantConditionRBACReady,
+	}
+	if tenant.Spec.EnforceImageSignatures {
+		conditions = append(conditions, platformv1alpha1.TenantConditionImagePolicyEnforced)
+	}
+	return conditions
+}
+
+// setCondition records a condition on the in-memory Tenant.
+// Status is persisted once per reconcile by updateStatus.
+func setCondition(
+	tenant *platformv1alpha1.Tenant,
+	conditionType string,
+	status metav1.ConditionStatus,
+	reason, message string,
+) {
+	meta.SetStatusCondition(&tenant.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: tenant.Generation,
+	})
+}
+
+// markStep sets conditionType from the outcome of a reconcile step and
+// returns err unchanged so callers can propagate it.
+func markStep(
+	tenant *platformv1alpha1.Tenant,
+	conditionType string,
+	err error,
+	reason, message string,
+) error {
+	if err != nil {
+		setCondition(tenant, conditionType, metav1.ConditionFalse, reasonReconcileError, err.Error())
+		return err
+	}
+	setCondition(tenant, conditionType, metav1.ConditionTrue, reason, message)
+	return nil
+}
+
+// tenantPhase summarises the conditions: a deleted Tenant is Terminating,
+// any False readiness condition is an Error, all of them True is Ready,
+// anything else is still Pending.
+func tenantPhase(tenant *platformv1alpha1.Tenant) string {
+	if !tenant.DeletionTimestamp.IsZero() {
+		return platformv1alpha1.TenantPhaseTerminating
+	}
+	readiness := readinessConditions(tenant)
+	for _, t := range readiness {
+		if meta.IsStatusConditionFalse(tenant.Status.Conditions, t) {
+			return platformv1alpha1.TenantPhaseError
+		}
+	}
+	for _, t := range readiness {
+		if !meta.IsStatusConditionTrue(tenant.Status.Conditions, t) {
+			return platformv1alpha1.TenantPhasePending
+		}
+	}
+	return platformv1alpha1.TenantPhaseReady
+}
+
+// updateStatus derives the phase and patches status when it differs from original.
+func (r *TenantReconciler) updateStatus(
+	ctx context.Context,
+	original, tenant *platformv1alpha1.Tenant,
+) error {
+	tenant.Status.Phase = tenantPhase(tenant)
+	if equality.Semantic.DeepEqual(original.Status, tenant.Status) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, tenant, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to update tenant status: %w", err)
+	}
+	return nil
+}
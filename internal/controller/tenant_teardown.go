@@ -0,0 +1,298 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// tenantFinalizer holds the Tenant until its namespace has been torn down.
+	tenantFinalizer = "platform.shieldx.io/finalizer"
+
+	// hookLabel marks Jobs in the tenant namespace that run before teardown,
+	// e.g. to export or back up data. Create them suspended; the controller
+	// resumes them and waits for them to complete.
+	hookLabel      = "platform.shieldx.io/hook"
+	hookPreDelete  = "pre-delete"
+	teardownPoll   = 5 * time.Second
+	hookFailedPoll = 30 * time.Second
+
+	// retainedLabel marks a namespace kept by deletionPolicy Retain. A Tenant
+	// with the same name and namespace, or one named in the adopt annotation,
+	// adopts it again and clears the label.
+	retainedLabel = platformv1alpha1.RetainedLabel
+)
+
+// Teardown steps reported as the reason of the Deleting condition.
+const (
+	teardownScalingDown         = "ScalingDown"
+	teardownRunningHooks        = "RunningHooks"
+	teardownHookFailed          = "HookFailed"
+	teardownDeletingResources   = "DeletingResources"
+	teardownWaitingForNamespace = "WaitingForNamespace"
+	teardownRetainingNamespace  = "RetainingNamespace"
+)
+
+// teardown runs one pass of the ordered deletion of a Tenant: scale down,
+// pre-delete hooks, child resources, then the namespace. It requeues until
+// the namespace is gone and then releases the finalizer.
+func (r *TenantReconciler) teardown(ctx context.Context, tenant *platformv1alpha1.Tenant) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(tenant, tenantFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	original := tenant.DeepCopy()
+	result, step, err := r.teardownStep(ctx, tenant)
+	if step != "" {
+		msg := fmt.Sprintf("Tearing down namespace %s: %s", tenantNamespaceName(tenant), step)
+		if cond := meta.FindStatusCondition(tenant.Status.Conditions, platformv1alpha1.TenantConditionDeleting); cond == nil || cond.Reason != step {
+			r.event(tenant, corev1.EventTypeNormal, step, msg)
+		}
+		setCondition(tenant, platformv1alpha1.TenantConditionDeleting, metav1.ConditionTrue, step, msg)
+		if statusErr := r.updateStatus(ctx, original, tenant); statusErr != nil && !apierrors.IsNotFound(statusErr) {
+			return ctrl.Result{}, statusErr
+		}
+		return result, err
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Namespace is gone or retained: release the Tenant.
+	controllerutil.RemoveFinalizer(tenant, tenantFinalizer)
+	if err := r.Update(ctx, tenant); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	outcome := "removed"
+	if tenant.Spec.DeletionPolicy == platformv1alpha1.DeletionPolicyRetain {
+		outcome = "retained"
+	}
+	log.Info("Tenant deleted", "tenant", tenant.Name, "namespace", tenantNamespaceName(tenant), "outcome", outcome)
+	r.event(tenant, corev1.EventTypeNormal, "Deleted", "Tenant namespace "+tenantNamespaceName(tenant)+" "+outcome)
+	r.notify("Tenant " + tenant.Name + " deleted, namespace " + tenantNamespaceName(tenant) + " " + outcome)
+	return ctrl.Result{}, nil
+}
+
+// teardownStep advances the teardown and returns the step still in progress,
+// or an empty step once nothing is left in the cluster.
+func (r *TenantReconciler) teardownStep(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) (ctrl.Result, string, error) {
+
+	nsName := tenantNamespaceName(tenant)
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: nsName}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, "", nil
+		}
+		return ctrl.Result{}, teardownWaitingForNamespace, fmt.Errorf("failed to get namespace %q: %w", nsName, err)
+	}
+	// Never delete a namespace this tenant did not create.
+	if ns.Labels[tenantNameLabel] != tenant.Name || ns.Labels[tenantNamespaceLabel] != tenant.Namespace {
+		return ctrl.Result{}, "", nil
+	}
+	if !ns.DeletionTimestamp.IsZero() {
+		return ctrl.Result{RequeueAfter: teardownPoll}, teardownWaitingForNamespace, nil
+	}
+
+	if tenant.Spec.DeletionPolicy == platformv1alpha1.DeletionPolicyRetain {
+		if err := r.retainNamespace(ctx, &ns); err != nil {
+			return ctrl.Result{}, teardownRetainingNamespace, err
+		}
+		return ctrl.Result{}, "", nil
+	}
+
+	if tenant.Spec.ScaleDownOnDelete {
+		done, err := r.scaleDownWorkloads(ctx, nsName)
+		if err != nil || !done {
+			return ctrl.Result{RequeueAfter: teardownPoll}, teardownScalingDown, err
+		}
+	}
+
+	done, failed, err := r.runPreDeleteHooks(ctx, nsName)
+	switch {
+	case err != nil:
+		return ctrl.Result{}, teardownRunningHooks, err
+	case failed != "":
+		// Deleting the failed Job lets teardown continue.
+		r.event(tenant, corev1.EventTypeWarning, teardownHookFailed, "pre-delete hook Job "+failed+" failed")
+		return ctrl.Result{RequeueAfter: hookFailedPoll}, teardownHookFailed, nil
+	case !done:
+		return ctrl.Result{RequeueAfter: teardownPoll}, teardownRunningHooks, nil
+	}
+
+	if err := r.deleteChildren(ctx, tenant); err != nil {
+		return ctrl.Result{}, teardownDeletingResources, err
+	}
+	if err := r.Delete(ctx, &ns); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, teardownDeletingResources, fmt.Errorf("failed to delete namespace %q: %w", nsName, err)
+	}
+	return ctrl.Result{RequeueAfter: teardownPoll}, teardownWaitingForNamespace, nil
+}
+
+// retainNamespace orphans the namespace with everything in it. The tier
+// label is dropped so the namespace no longer counts against the tier.
+func (r *TenantReconciler) retainNamespace(ctx context.Context, ns *corev1.Namespace) error {
+	patch := client.MergeFrom(ns.DeepCopy())
+	delete(ns.Labels, tierLabel)
+	ns.Labels[retainedLabel] = "true"
+	if err := r.Patch(ctx, ns, patch); err != nil {
+		return fmt.Errorf("failed to label retained namespace %q: %w", ns.Name, err)
+	}
+	return nil
+}
+
+// scaleDownWorkloads sets every Deployment and StatefulSet to zero replicas
+// and reports whether all their pods are gone.
+func (r *TenantReconciler) scaleDownWorkloads(ctx context.Context, namespace string) (bool, error) {
+	done := true
+
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if d.Spec.Replicas == nil || *d.Spec.Replicas != 0 {
+			patch := client.MergeFrom(d.DeepCopy())
+			d.Spec.Replicas = ptr.To[int32](0)
+			if err := r.Patch(ctx, d, patch); err != nil {
+				return false, fmt.Errorf("failed to scale down deployment %q: %w", d.Name, err)
+			}
+		}
+		if d.Status.Replicas > 0 {
+			done = false
+		}
+	}
+
+	var statefulSets appsv1.StatefulSetList
+	if err := r.List(ctx, &statefulSets, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		if s.Spec.Replicas == nil || *s.Spec.Replicas != 0 {
+			patch := client.MergeFrom(s.DeepCopy())
+			s.Spec.Replicas = ptr.To[int32](0)
+			if err := r.Patch(ctx, s, patch); err != nil {
+				return false, fmt.Errorf("failed to scale down statefulset %q: %w", s.Name, err)
+			}
+		}
+		if s.Status.Replicas > 0 {
+			done = false
+		}
+	}
+
+	return done, nil
+}
+
+// runPreDeleteHooks resumes suspended pre-delete hook Jobs and reports
+// whether all of them completed, or the name of one that failed.
+func (r *TenantReconciler) runPreDeleteHooks(ctx context.Context, namespace string) (bool, string, error) {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs, client.InNamespace(namespace), client.MatchingLabels{hookLabel: hookPreDelete}); err != nil {
+		return false, "", fmt.Errorf("failed to list pre-delete hooks: %w", err)
+	}
+
+	done := true
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		switch {
+		case jobFinished(job, batchv1.JobComplete):
+			continue
+		case jobFinished(job, batchv1.JobFailed):
+			return false, job.Name, nil
+		}
+		done = false
+		if ptr.Deref(job.Spec.Suspend, false) {
+			patch := client.MergeFrom(job.DeepCopy())
+			job.Spec.Suspend = ptr.To(false)
+			if err := r.Patch(ctx, job, patch); err != nil {
+				return false, "", fmt.Errorf("failed to start pre-delete hook %q: %w", job.Name, err)
+			}
+		}
+	}
+	return done, "", nil
+}
+
+func jobFinished(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteChildren removes what the controller created in the tenant namespace,
+// revoking access first so nobody acts on a half-deleted tenant.
+func (r *TenantReconciler) deleteChildren(ctx context.Context, tenant *platformv1alpha1.Tenant) error {
+	opts := []client.DeleteAllOfOption{
+		client.InNamespace(tenantNamespaceName(tenant)),
+		client.MatchingLabels{tenantNameLabel: tenant.Name, tenantNamespaceLabel: tenant.Namespace},
+	}
+	for _, obj := range []client.Object{
+		&rbacv1.RoleBinding{},
+		&networkingv1.NetworkPolicy{},
+		&corev1.LimitRange{},
+		&corev1.ResourceQuota{},
+	} {
+		if err := r.DeleteAllOf(ctx, obj, opts...); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %T: %w", obj, err)
+		}
+	}
+	return nil
+}
+
+// event records an Event on the Tenant or one of its objects when a recorder is configured.
+func (r *TenantReconciler) event(obj runtime.Object, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(obj, eventType, reason, message)
+	}
+}
+
+// notify sends a best-effort notification when a notifier is configured.
+func (r *TenantReconciler) notify(message string) {
+	if r.Notify == nil {
+		return
+	}
+	if err := r.Notify(message); err != nil {
+		logf.Log.WithName("tenant").Error(err, "failed to send notification")
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// tenantTierField indexes Tenants by the TenantTier they reference.
+	tenantTierField = "spec.tier"
+
+	// tierLabel is set on tenant namespaces to count them per tier.
+	tierLabel = platformv1alpha1.TierLabel
+
+	// priorityClassAnnotation publishes the tier PriorityClass on the tenant namespace.
+	priorityClassAnnotation = platformv1alpha1.PriorityClassAnnotation
+
+	limitRangeName = "tenant-limits"
+)
+
+// tierName returns the TenantTier object name referenced by the tenant.
+// Tiers are matched case-insensitively ("Gold" resolves to TenantTier "gold").
+func tierName(tenant *platformv1alpha1.Tenant) string {
+	return strings.ToLower(strings.TrimSpace(tenant.Spec.Tier))
+}
+
+// resolveTier fetches the TenantTier referenced by spec.tier.
+// It returns nil without error when the tier does not exist.
+func (r *TenantReconciler) resolveTier(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) (*platformv1alpha1.TenantTier, error) {
+	name := tierName(tenant)
+	if name == "" {
+		return nil, nil
+	}
+
+	var tier platformv1alpha1.TenantTier
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, &tier); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get TenantTier %q: %w", name, err)
+	}
+	return &tier, nil
+}
+
+// tierIsFull reports whether provisioning the tenant namespace would exceed
+// the tier's maxNamespaces. Tenants whose namespace already exists are never blocked.
+func (r *TenantReconciler) tierIsFull(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	tier *platformv1alpha1.TenantTier,
+) (bool, error) {
+	if tier.Spec.MaxNamespaces <= 0 {
+		return false, nil
+	}
+
+	var ns corev1.Namespace
+	err := r.Get(ctx, client.ObjectKey{Name: tenantNamespaceName(tenant)}, &ns)
+	if err == nil {
+		return false, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get namespace %q: %w", tenantNamespaceName(tenant), err)
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabels{tierLabel: tier.Name}); err != nil {
+		return false, fmt.Errorf("failed to list namespaces of tier %q: %w", tier.Name, err)
+	}
+	return int32(len(namespaces.Items)) >= tier.Spec.MaxNamespaces, nil
+}
+
+// quantityField is one named quantity taken from a spec.
+type quantityField struct {
+	name  corev1.ResourceName
+	path  string
+	value string
+}
+
+// parseQuantities parses every non-empty field and describes each invalid one.
+func parseQuantities(fields []quantityField) (corev1.ResourceList, []string) {
+	out := corev1.ResourceList{}
+	var problems []string
+	for _, f := range fields {
+		v := strings.TrimSpace(f.value)
+		if v == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid quantity %q", f.path, v))
+			continue
+		}
+		out[f.name] = q
+	}
+	return out, problems
+}
+
+// desiredLimitRange builds the container LimitRange item of a tier.
+// It returns nil when the tier defines no limits.
+func desiredLimitRange(tier *platformv1alpha1.TenantTier) (*corev1.LimitRangeItem, []string) {
+	lr := tier.Spec.LimitRange
+	prefix := "tenanttier " + tier.Name + ": spec.limitRange."
+
+	def, p1 := parseQuantities([]quantityField{
+		{corev1.ResourceCPU, prefix + "defaultCPU", lr.DefaultCPU},
+		{corev1.ResourceMemory, prefix + "defaultMemory", lr.DefaultMemory},
+	})
+	defReq, p2 := parseQuantities([]quantityField{
+		{corev1.ResourceCPU, prefix + "defaultRequestCPU", lr.DefaultRequestCPU},
+		{corev1.ResourceMemory, prefix + "defaultRequestMemory", lr.DefaultRequestMemory},
+	})
+	maxLimits, p3 := parseQuantities([]quantityField{
+		{corev1.ResourceCPU, prefix + "maxCPU", lr.MaxCPU},
+		{corev1.ResourceMemory, prefix + "maxMemory", lr.MaxMemory},
+	})
+
+	problems := append(append(p1, p2...), p3...)
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	if len(def) == 0 && len(defReq) == 0 && len(maxLimits) == 0 {
+		return nil, nil
+	}
+
+	item := &corev1.LimitRangeItem{Type: corev1.LimitTypeContainer}
+	if len(def) > 0 {
+		item.Default = def
+	}
+	if len(defReq) > 0 {
+		item.DefaultRequest = defReq
+	}
+	if len(maxLimits) > 0 {
+		item.Max = maxLimits
+	}
+	return item, nil
+}
+
+// ensureLimitRange keeps the tenant LimitRange in sync with the tier,
+// deleting it when the tier no longer defines limits.
+func (r *TenantReconciler) ensureLimitRange(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	item *corev1.LimitRangeItem,
+) error {
+
+	lr := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      limitRangeName,
+			Namespace: tenantNamespaceName(tenant),
+		},
+	}
+
+	if item == nil {
+		if err := r.Delete(ctx, lr); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete LimitRange %s/%s: %w", lr.Namespace, lr.Name, err)
+		}
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(
+		ctx,
+		r.Client,
+		lr,
+		func() error {
+			setTenantLabels(lr, tenant)
+			lr.Spec.Limits = []corev1.LimitRangeItem{*item}
+			return nil
+		},
+	)
+
+	return err
+}
+
+// tenantsForTier re-queues every Tenant referencing a TenantTier that changed.
+func (r *TenantReconciler) tenantsForTier(ctx context.Context, obj client.Object) []reconcile.Request {
+	var tenants platformv1alpha1.TenantList
+	if err := r.List(ctx, &tenants, client.MatchingFields{tenantTierField: obj.GetName()}); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list tenants of tier", "tier", obj.GetName())
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, 0, len(tenants.Items))
+	for _, t := range tenants.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: t.Name, Namespace: t.Namespace}})
+	}
+	return reqs
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxOwnerDepth bounds the ownerReference walk, e.g. Pod → Job → CronJob.
+const maxOwnerDepth = 4
+
+// workloadCache remembers the top-level workload of each controller seen
+// during one scan, so replicas of the same ReplicaSet are resolved once.
+type workloadCache map[types.UID]client.Object
+
+// resolveWorkload follows the controller ownerReferences of pod up to the
+// top-level workload: a Deployment, StatefulSet, DaemonSet, CronJob, or a
+// ReplicaSet or Job nothing controls. A pod without a known controller is its
+// own workload, and so is one whose controller was deleted.
+func (r *TenantReconciler) resolveWorkload(ctx context.Context, pod *corev1.Pod, cache workloadCache) (client.Object, error) {
+	var (
+		obj     client.Object = pod
+		visited []types.UID
+	)
+	for range maxOwnerDepth {
+		ref := metav1.GetControllerOf(obj)
+		if ref == nil {
+			break
+		}
+		if top, ok := cache[ref.UID]; ok {
+			obj = top
+			break
+		}
+		owner := newWorkload(ref)
+		if owner == nil {
+			break
+		}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: ref.Name}, owner); err != nil {
+			if apierrors.IsNotFound(err) {
+				break
+			}
+			return nil, fmt.Errorf("failed to get %s %q: %w", ref.Kind, ref.Name, err)
+		}
+		visited = append(visited, ref.UID)
+		obj = owner
+	}
+
+	for _, uid := range visited {
+		cache[uid] = obj
+	}
+	return obj, nil
+}
+
+// newWorkload returns an empty object of the workload kind ref points to, or
+// nil for kinds the scanner does not act on.
+func newWorkload(ref *metav1.OwnerReference) client.Object {
+	switch ref.APIVersion {
+	case appsv1.SchemeGroupVersion.String():
+		switch ref.Kind {
+		case "ReplicaSet":
+			return &appsv1.ReplicaSet{}
+		case "Deployment":
+			return &appsv1.Deployment{}
+		case "StatefulSet":
+			return &appsv1.StatefulSet{}
+		case "DaemonSet":
+			return &appsv1.DaemonSet{}
+		}
+	case batchv1.SchemeGroupVersion.String():
+		switch ref.Kind {
+		case "Job":
+			return &batchv1.Job{}
+		case "CronJob":
+			return &batchv1.CronJob{}
+		}
+	}
+	return nil
+}
+
+// workloadKind names the kind of a workload returned by resolveWorkload.
+func workloadKind(obj client.Object) string {
+	switch obj.(type) {
+	case *appsv1.ReplicaSet:
+		return "ReplicaSet"
+	case *appsv1.Deployment:
+		return "Deployment"
+	case *appsv1.StatefulSet:
+		return "StatefulSet"
+	case *appsv1.DaemonSet:
+		return "DaemonSet"
+	case *batchv1.Job:
+		return "Job"
+	case *batchv1.CronJob:
+		return "CronJob"
+	}
+	return "Pod"
+}
+
+// workloadReplicas returns the replicas of workloads that can be scaled, or nil.
+func workloadReplicas(obj client.Object) **int32 {
+	switch w := obj.(type) {
+	case *appsv1.ReplicaSet:
+		return &w.Spec.Replicas
+	case *appsv1.Deployment:
+		return &w.Spec.Replicas
+	case *appsv1.StatefulSet:
+		return &w.Spec.Replicas
+	}
+	return nil
+}
+
+// workloadSuspend returns the suspend flag of Jobs and CronJobs, or nil.
+func workloadSuspend(obj client.Object) **bool {
+	switch w := obj.(type) {
+	case *batchv1.Job:
+		return &w.Spec.Suspend
+	case *batchv1.CronJob:
+		return &w.Spec.Suspend
+	}
+	return nil
+}
+
+// workloadPodSpec returns the spec of the pods a workload creates.
+func workloadPodSpec(obj client.Object) *corev1.PodSpec {
+	switch w := obj.(type) {
+	case *corev1.Pod:
+		return &w.Spec
+	case *appsv1.ReplicaSet:
+		return &w.Spec.Template.Spec
+	case *appsv1.Deployment:
+		return &w.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template.Spec
+	case *batchv1.Job:
+		return &w.Spec.Template.Spec
+	case *batchv1.CronJob:
+		return &w.Spec.JobTemplate.Spec.Template.Spec
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package identity parses the identities listed in Tenant owners, members and viewers.
+package identity
+
+import (
+	"fmt"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Prefixes selecting the subject kind; entries without a prefix are users.
+const (
+	GroupPrefix          = "group:"
+	ServiceAccountPrefix = "sa:"
+)
+
+// ParseSubject maps an identity to an RBAC subject:
+// "group:<name>" is a Group, "sa:[<namespace>/]<name>" a ServiceAccount
+// (in namespace when omitted) and anything else a User.
+func ParseSubject(entry, namespace string) (rbacv1.Subject, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.ContainsAny(entry, " \t\n") {
+		return rbacv1.Subject{}, fmt.Errorf("%q: identity must not contain whitespace", entry)
+	}
+	switch {
+	case strings.HasPrefix(entry, GroupPrefix):
+		name := strings.TrimPrefix(entry, GroupPrefix)
+		if name == "" {
+			return rbacv1.Subject{}, fmt.Errorf("%q: group name is empty", entry)
+		}
+		return rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: name}, nil
+
+	case strings.HasPrefix(entry, ServiceAccountPrefix):
+		ref := strings.TrimPrefix(entry, ServiceAccountPrefix)
+		ns, name := namespace, ref
+		if i := strings.Index(ref, "/"); i >= 0 {
+			ns, name = ref[:i], ref[i+1:]
+		}
+		for _, part := range []string{ns, name} {
+			if errs := validation.IsDNS1123Subdomain(part); len(errs) > 0 {
+				return rbacv1.Subject{}, fmt.Errorf("%q: invalid service account: %s", entry, strings.Join(errs, ", "))
+			}
+		}
+		return rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: ns, Name: name}, nil
+
+	case entry == "":
+		return rbacv1.Subject{}, fmt.Errorf("identity is empty")
+	}
+	return rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: entry}, nil
+}
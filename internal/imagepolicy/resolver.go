@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagepolicy selects the TenantImagePolicy or ClusterImagePolicy
+// that applies to an image, shared by the scanner and the admission webhooks.
+package imagepolicy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+)
+
+// Resolver looks up the image policies that apply in a namespace.
+type Resolver struct {
+	// Client lists TenantImagePolicies and ClusterImagePolicies; only Default
+	// applies when nil.
+	Client client.Reader
+	// Default applies to images no policy object matches. Its keys, keyring
+	// and identities are also used by policies that trust none of their own.
+	Default verifyimage.Policy
+	// DefaultAction is the action of Default, ImagePolicyActionDelete when empty.
+	DefaultAction string
+}
+
+// Decision is the policy that applies to one image.
+type Decision struct {
+	Policy verifyimage.Policy
+	// Mode is ImagePolicyModeEnforce, ImagePolicyModeWarn or ImagePolicyModeAudit.
+	Mode string
+	// Action is ImagePolicyActionDelete or ImagePolicyActionQuarantine.
+	Action string
+	// Exempt images are admitted without verification.
+	Exempt bool
+}
+
+// Enforced reports whether failing images must be blocked.
+func (d Decision) Enforced() bool {
+	return d.Mode != platformv1alpha1.ImagePolicyModeAudit && d.Mode != platformv1alpha1.ImagePolicyModeWarn
+}
+
+// Set holds the policies that apply in one namespace.
+type Set struct {
+	tenant    []candidate
+	cluster   []candidate
+	def       verifyimage.Policy
+	defAction string
+}
+
+type candidate struct {
+	name string
+	spec platformv1alpha1.ImagePolicySpec
+}
+
+// Policies returns the policies that apply to images in namespace. A nil
+// Resolver trusts nothing, so every image fails verification.
+func (r *Resolver) Policies(ctx context.Context, namespace string) (Set, error) {
+	if r == nil {
+		return Set{}, nil
+	}
+	set := Set{def: r.Default, defAction: r.DefaultAction}
+	if r.Client == nil {
+		return set, nil
+	}
+
+	var tenant platformv1alpha1.TenantImagePolicyList
+	if err := r.Client.List(ctx, &tenant, client.InNamespace(namespace)); err != nil {
+		return Set{}, fmt.Errorf("list TenantImagePolicies in %q: %w", namespace, err)
+	}
+	for _, p := range tenant.Items {
+		set.tenant = append(set.tenant, candidate{"TenantImagePolicy/" + p.Namespace + "/" + p.Name, p.Spec})
+	}
+	var cluster platformv1alpha1.ClusterImagePolicyList
+	if err := r.Client.List(ctx, &cluster); err != nil {
+		return Set{}, fmt.Errorf("list ClusterImagePolicies: %w", err)
+	}
+	for _, p := range cluster.Items {
+		set.cluster = append(set.cluster, candidate{"ClusterImagePolicy/" + p.Name, p.Spec})
+	}
+
+	// Break ties between equally specific patterns by name.
+	for _, c := range [][]candidate{set.tenant, set.cluster} {
+		sort.Slice(c, func(i, j int) bool { return c[i].name < c[j].name })
+	}
+	return set, nil
+}
+
+// For returns the decision for image. TenantImagePolicies take precedence
+// over ClusterImagePolicies; within each, the longest matching pattern wins.
+// An error means the matching policy is invalid and the image cannot be
+// admitted.
+func (s Set) For(image string) (Decision, error) {
+	names := imageNames(image)
+	for _, candidates := range [][]candidate{s.tenant, s.cluster} {
+		best, bestLen := -1, -1
+		for i, c := range candidates {
+			if n := longestMatch(c.spec.Images, names); n > bestLen {
+				best, bestLen = i, n
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		c := candidates[best]
+		d := Decision{Mode: c.spec.Mode, Action: action(c.spec.Action), Exempt: longestMatch(c.spec.Exemptions, names) >= 0}
+		if d.Mode == "" {
+			d.Mode = platformv1alpha1.ImagePolicyModeEnforce
+		}
+		policy, err := toPolicy(c.name, c.spec, s.def)
+		if err != nil {
+			return d, fmt.Errorf("invalid %s: %w", c.name, err)
+		}
+		d.Policy = policy
+		return d, nil
+	}
+	return Decision{Policy: s.def, Mode: platformv1alpha1.ImagePolicyModeEnforce, Action: action(s.defAction)}, nil
+}
+
+// Trusts reports whether any policy applies in the namespace: a policy object,
+// or a default that trusts some key or identity. Without one every image fails.
+func (s Set) Trusts() bool {
+	return len(s.tenant) > 0 || len(s.cluster) > 0 ||
+		len(s.def.ActiveKeys(time.Now())) > 0 || len(s.def.Identities) > 0
+}
+
+func action(a string) string {
+	if a == "" {
+		return platformv1alpha1.ImagePolicyActionDelete
+	}
+	return a
+}
+
+// imageNames returns image as written and fully qualified, e.g. "nginx" and
+// "index.docker.io/library/nginx:latest", so patterns can use either form.
+func imageNames(image string) []string {
+	names := []string{image}
+	if ref, err := name.ParseReference(image); err == nil && ref.Name() != image {
+		names = append(names, ref.Name())
+	}
+	return names
+}
+
+// longestMatch returns the length of the longest pattern matching one of
+// names, or -1 when none does.
+func longestMatch(patterns, names []string) int {
+	longest := -1
+	for _, p := range patterns {
+		for _, n := range names {
+			if len(p) > longest && verifyimage.MatchGlob(p, n) {
+				longest = len(p)
+			}
+		}
+	}
+	return longest
+}
+
+// toPolicy converts spec into a verification policy named name.
+func toPolicy(name string, spec platformv1alpha1.ImagePolicySpec, def verifyimage.Policy) (verifyimage.Policy, error) {
+	policy := verifyimage.Policy{Name: name, IgnoreTlog: spec.IgnoreTlog}
+	for _, k := range spec.Keys {
+		key := verifyimage.Key{Name: k.Name, PEM: k.PublicKey}
+		if k.NotAfter != nil {
+			key.NotAfter = k.NotAfter.Time
+		}
+		policy.Keys = append(policy.Keys, key)
+	}
+	for _, id := range spec.Identities {
+		identity := verifyimage.Identity(id)
+		if err := identity.Validate(); err != nil {
+			return verifyimage.Policy{}, err
+		}
+		policy.Identities = append(policy.Identities, identity)
+	}
+	for _, a := range spec.Attestations {
+		rule := verifyimage.AttestationRule(a)
+		if err := rule.Validate(); err != nil {
+			return verifyimage.Policy{}, err
+		}
+		policy.Attestations = append(policy.Attestations, rule)
+	}
+	if len(policy.Keys) == 0 && len(policy.Identities) == 0 {
+		policy.Keys, policy.Keyring, policy.Identities = def.Keys, def.Keyring, def.Identities
+	}
+	return policy, nil
+}
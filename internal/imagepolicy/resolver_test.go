@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+)
+
+func TestResolverPicksMostSpecificPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := platformv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&platformv1alpha1.ClusterImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images:     []string{"*"},
+				Exemptions: []string{"registry.k8s.io/*"},
+				Mode:       platformv1alpha1.ImagePolicyModeAudit,
+			},
+		},
+		&platformv1alpha1.ClusterImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "shieldx"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images: []string{"ghcr.io/shieldx-bot/*"},
+				Keys:   []platformv1alpha1.ImagePolicyKey{{Name: "ci", PublicKey: "pem"}},
+				Action: platformv1alpha1.ImagePolicyActionQuarantine,
+			},
+		},
+		&platformv1alpha1.TenantImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "acme", Namespace: "tenant-acme"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images:       []string{"ghcr.io/*"},
+				Attestations: []platformv1alpha1.ImagePolicyAttestation{{PredicateType: "cyclonedx"}},
+			},
+		},
+		&platformv1alpha1.TenantImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "tenant-broken"},
+			Spec: platformv1alpha1.ImagePolicySpec{
+				Images:     []string{"*"},
+				Identities: []platformv1alpha1.ImagePolicyIdentity{{Subject: "ci@example.com"}},
+			},
+		},
+	).Build()
+	def := verifyimage.Policy{Name: "default", Keys: []verifyimage.Key{{Name: "env", PEM: "pem"}}}
+	r := &Resolver{Client: c, Default: def}
+
+	for _, tc := range []struct {
+		namespace, image, policy string
+		audit, exempt            bool
+	}{
+		{"tenant-other", "ghcr.io/shieldx-bot/api:v1", "ClusterImagePolicy/shieldx", false, false},
+		{"tenant-other", "nginx:1.27", "ClusterImagePolicy/baseline", true, false},
+		{"tenant-other", "registry.k8s.io/pause:3.10", "ClusterImagePolicy/baseline", true, true},
+		{"tenant-acme", "ghcr.io/shieldx-bot/api:v1", "TenantImagePolicy/tenant-acme/acme", false, false},
+		{"tenant-acme", "index.docker.io/library/nginx:1.27", "ClusterImagePolicy/baseline", true, false},
+	} {
+		set, err := r.Policies(context.Background(), tc.namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := set.For(tc.image)
+		if err != nil {
+			t.Fatalf("For(%q) in %s: %v", tc.image, tc.namespace, err)
+		}
+		if d.Policy.Name != tc.policy || d.Enforced() == tc.audit || d.Exempt != tc.exempt {
+			t.Errorf("For(%q) in %s = %s (mode %s, exempt %t), want %s (audit %t, exempt %t)",
+				tc.image, tc.namespace, d.Policy.Name, d.Mode, d.Exempt, tc.policy, tc.audit, tc.exempt)
+		}
+	}
+
+	set, err := r.Policies(context.Background(), "tenant-other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := set.For("ghcr.io/shieldx-bot/api:v1"); d.Action != platformv1alpha1.ImagePolicyActionQuarantine {
+		t.Errorf("expected the action of the matching policy, got %q", d.Action)
+	}
+	if d, _ := set.For("nginx:1.27"); d.Action != platformv1alpha1.ImagePolicyActionDelete {
+		t.Errorf("expected policies without an action to delete, got %q", d.Action)
+	}
+
+	set, err = r.Policies(context.Background(), "tenant-acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _ := set.For("ghcr.io/shieldx-bot/api:v1")
+	if len(d.Policy.Keys) != 1 || d.Policy.Keys[0].Name != "env" {
+		t.Errorf("expected a policy without keys to inherit the default keys, got %+v", d.Policy.Keys)
+	}
+
+	set, err = r.Policies(context.Background(), "tenant-broken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.For("nginx"); err == nil {
+		t.Error("expected an invalid identity to fail the lookup")
+	}
+}
+
+func TestResolverWithoutClientUsesDefault(t *testing.T) {
+	set, err := (&Resolver{Default: verifyimage.Policy{Name: "default"}}).Policies(context.Background(), "tenant-acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := set.For("nginx")
+	if err != nil || d.Policy.Name != "default" || !d.Enforced() {
+		t.Fatalf("For() = %+v, %v, want the enforced default policy", d, err)
+	}
+	if set.Trusts() {
+		t.Error("a default without keys or identities should trust nothing")
+	}
+
+	set, _ = (&Resolver{Default: verifyimage.Policy{Keys: []verifyimage.Key{{Name: "env", PEM: "pem"}}}}).
+		Policies(context.Background(), "tenant-acme")
+	if !set.Trusts() {
+		t.Error("a default with a key should be trusted")
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupPodDigestWebhookWithManager registers the digest pinning webhook for Pods in the manager.
+func SetupPodDigestWebhookWithManager(
+	mgr ctrl.Manager,
+	verifier verifyimage.Verifier,
+	policies *imagepolicy.Resolver,
+) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).
+		WithDefaulter(&PodDigestDefaulter{Client: mgr.GetClient(), Verifier: verifier, Policies: policies}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods;pods/ephemeralcontainers,verbs=create;update,versions=v1,name=mpod-v1.kb.io,admissionReviewVersions=v1,timeoutSeconds=30
+
+// PodDigestDefaulter pins the images of pods in enforced namespaces to the
+// digest whose signature was verified, so the kubelet cannot pull a different
+// image for the same tag afterwards.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as it is used only for temporary operations and does not need to be deeply copied.
+type PodDigestDefaulter struct {
+	// Client looks up the namespace label; every namespace is enforced when nil.
+	Client client.Reader
+	// Verifier checks image signatures against the policy Policies selects
+	// and reports the verified digest.
+	Verifier verifyimage.Verifier
+	Policies *imagepolicy.Resolver
+}
+
+var _ webhook.CustomDefaulter = &PodDigestDefaulter{}
+
+// Default implements webhook.CustomDefaulter. Images that fail verification
+// are left untouched for the validating webhook to reject with its reason.
+//
+// Images are pinned only when a pod is created, and on the ephemeralcontainers
+// subresource only for newly added containers: changing the image of a running
+// container restarts it, so other updates never rewrite images.
+func (d *PodDigestDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+	existing, pin, err := pinnableContainers(ctx)
+	if err != nil || !pin {
+		return err
+	}
+
+	enforced, err := imagePolicyEnforced(ctx, d.Client, requestNamespace(ctx, pod))
+	if err != nil || !enforced {
+		return err
+	}
+	if d.Verifier == nil {
+		return fmt.Errorf("no image verifier configured")
+	}
+	policies, err := d.Policies.Policies(ctx, requestNamespace(ctx, pod))
+	if err != nil {
+		return err
+	}
+
+	original := map[string]string{}
+	if raw := pod.Annotations[platformv1alpha1.OriginalImagesAnnotation]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &original); err != nil {
+			workloadimagelog.Info("Ignoring malformed annotation", "pod", pod.Name,
+				"annotation", platformv1alpha1.OriginalImagesAnnotation, "error", err.Error())
+			original = map[string]string{}
+		}
+	}
+
+	pinned := map[string]string{}
+	changed := false
+	visitImages(&pod.Spec, func(container string, image *string) {
+		if *image == "" || strings.Contains(*image, "@") || existing[container] {
+			return
+		}
+		ref, done := pinned[*image]
+		if !done {
+			decision, err := policies.For(*image)
+			if err != nil || decision.Exempt {
+				pinned[*image] = ""
+				return
+			}
+			res, err := d.Verifier.Verify(ctx, *image, decision.Policy)
+			if err != nil {
+				workloadimagelog.Info("Not pinning unverified image", "pod", pod.Name,
+					"container", container, "image", *image, "error", err.Error())
+			}
+			ref = res.Digest
+			pinned[*image] = ref
+		}
+		if ref == "" {
+			return
+		}
+		original[container] = *image
+		*image = ref
+		changed = true
+	})
+	if !changed {
+		return nil
+	}
+
+	raw, err := json.Marshal(original)
+	if err != nil {
+		return fmt.Errorf("failed to encode original images: %w", err)
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[platformv1alpha1.OriginalImagesAnnotation] = string(raw)
+	return nil
+}
+
+// pinnableContainers reports whether the admission request in ctx may pin
+// images and returns the containers that already ran before it, which must
+// keep their images. Requests without admission context are treated as creates.
+func pinnableContainers(ctx context.Context) (map[string]bool, bool, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.Operation == admissionv1.Create {
+		return nil, true, nil
+	}
+	if req.Operation != admissionv1.Update || req.SubResource != "ephemeralcontainers" {
+		return nil, false, nil
+	}
+
+	var old corev1.Pod
+	if err := json.Unmarshal(req.OldObject.Raw, &old); err != nil {
+		return nil, false, fmt.Errorf("failed to decode the existing pod: %w", err)
+	}
+	existing := map[string]bool{}
+	visitImages(&old.Spec, func(container string, _ *string) { existing[container] = true })
+	return existing, true, nil
+}
+
+// visitImages calls fn with the image field of every init, regular and ephemeral container.
+func visitImages(spec *corev1.PodSpec, fn func(container string, image *string)) {
+	for i := range spec.InitContainers {
+		fn(spec.InitContainers[i].Name, &spec.InitContainers[i].Image)
+	}
+	for i := range spec.Containers {
+		fn(spec.Containers[i].Name, &spec.Containers[i].Image)
+	}
+	for i := range spec.EphemeralContainers {
+		fn(spec.EphemeralContainers[i].Name, &spec.EphemeralContainers[i].Image)
+	}
+}
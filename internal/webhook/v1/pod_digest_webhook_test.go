@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage/fake"
+)
+
+var _ = Describe("Pod Digest Webhook", func() {
+	const digest = "@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	var defaulter PodDigestDefaulter
+
+	BeforeEach(func() {
+		enforced := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "tenant-acme",
+			Labels: map[string]string{platformv1alpha1.ImagePolicyLabel: platformv1alpha1.ImagePolicyEnforce},
+		}}
+		open := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		defaulter = PodDigestDefaulter{
+			Client: clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(enforced, open).Build(),
+			Verifier: &fake.Verifier{Digests: map[string]string{
+				"registry.example.com/app:1.0": "registry.example.com/app" + digest,
+			}},
+			Policies: &imagepolicy.Resolver{Default: verifyimage.Policy{Name: "default"}},
+		}
+	})
+
+	It("Should pin verified images and record the original tags", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "migrate", Image: "registry.example.com/app:1.0"}},
+				Containers: []corev1.Container{
+					{Name: "app", Image: "registry.example.com/app:1.0"},
+					{Name: "sidecar", Image: "registry.example.com/unsigned:1.0"},
+				},
+			},
+		}
+		Expect(defaulter.Default(ctx, pod)).To(Succeed())
+		Expect(pod.Spec.InitContainers[0].Image).To(Equal("registry.example.com/app" + digest))
+		Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/app" + digest))
+		By("leaving unverified images for the validating webhook")
+		Expect(pod.Spec.Containers[1].Image).To(Equal("registry.example.com/unsigned:1.0"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(platformv1alpha1.OriginalImagesAnnotation,
+			`{"app":"registry.example.com/app:1.0","migrate":"registry.example.com/app:1.0"}`))
+	})
+
+	It("Should leave pods outside enforced namespaces untouched", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/app:1.0"}}},
+		}
+		Expect(defaulter.Default(ctx, pod)).To(Succeed())
+		Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/app:1.0"))
+		Expect(pod.Annotations).To(BeEmpty())
+	})
+
+	It("Should not rewrite the images of an existing pod on update", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme", Labels: map[string]string{"quarantined": "true"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/app:1.0"}}},
+		}
+		updateCtx := admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+		}})
+		Expect(defaulter.Default(updateCtx, pod)).To(Succeed())
+		Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/app:1.0"))
+		Expect(pod.Annotations).To(BeEmpty())
+	})
+
+	It("Should pin only newly added ephemeral containers", func() {
+		old := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/app:1.0"}},
+				EphemeralContainers: []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+					Name: "debug-1", Image: "registry.example.com/app:1.0",
+				}}},
+			},
+		}
+		raw, err := json.Marshal(old)
+		Expect(err).NotTo(HaveOccurred())
+
+		pod := old.DeepCopy()
+		pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+			EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debug-2", Image: "registry.example.com/app:1.0"},
+		})
+		updateCtx := admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation:   admissionv1.Update,
+			SubResource: "ephemeralcontainers",
+			OldObject:   runtime.RawExtension{Raw: raw},
+		}})
+		Expect(defaulter.Default(updateCtx, pod)).To(Succeed())
+		Expect(pod.Spec.Containers[0].Image).To(Equal("registry.example.com/app:1.0"))
+		Expect(pod.Spec.EphemeralContainers[0].Image).To(Equal("registry.example.com/app:1.0"))
+		Expect(pod.Spec.EphemeralContainers[1].Image).To(Equal("registry.example.com/app" + digest))
+	})
+})
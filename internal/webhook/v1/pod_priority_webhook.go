@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// SetupPodPriorityWebhookWithManager registers the tier priority webhook for Pods in the manager.
+func SetupPodPriorityWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).
+		WithDefaulter(&PodPriorityDefaulter{Client: mgr.GetClient()}).
+		WithDefaulterCustomPath("/mutate-priority--v1-pod").
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-priority--v1-pod,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-priority-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
+
+// PodPriorityDefaulter gives pods in tenant namespaces the PriorityClass of
+// their tier, published on the namespace by the Tenant controller.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as it is used only for temporary operations and does not need to be deeply copied.
+type PodPriorityDefaulter struct {
+	// Client looks up the namespace annotation and the PriorityClass.
+	Client client.Reader
+}
+
+var _ webhook.CustomDefaulter = &PodPriorityDefaulter{}
+
+// Default implements webhook.CustomDefaulter. Pods that set their own
+// priorityClassName are left untouched.
+func (d *PodPriorityDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+	if pod.Spec.PriorityClassName != "" {
+		return nil
+	}
+
+	namespace := requestNamespace(ctx, pod)
+	var ns corev1.Namespace
+	if err := d.Client.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+	}
+	name := ns.Annotations[platformv1alpha1.PriorityClassAnnotation]
+	if name == "" {
+		return nil
+	}
+
+	// The Priority admission plugin resolved spec.priority before webhooks
+	// run, so the value and preemption policy are copied from the class too.
+	var pc schedulingv1.PriorityClass
+	if err := d.Client.Get(ctx, client.ObjectKey{Name: name}, &pc); err != nil {
+		if apierrors.IsNotFound(err) {
+			workloadimagelog.Info("Tier PriorityClass not found; leaving pod priority unset",
+				"pod", pod.Name, "namespace", namespace, "priorityClass", name)
+			return nil
+		}
+		return fmt.Errorf("failed to get PriorityClass %q: %w", name, err)
+	}
+	pod.Spec.PriorityClassName = pc.Name
+	pod.Spec.Priority = &pc.Value
+	pod.Spec.PreemptionPolicy = pc.PreemptionPolicy
+	return nil
+}
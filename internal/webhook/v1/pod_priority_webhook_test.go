@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+)
+
+var _ = Describe("Pod Priority Webhook", func() {
+	var defaulter PodPriorityDefaulter
+
+	BeforeEach(func() {
+		gold := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-acme",
+			Annotations: map[string]string{platformv1alpha1.PriorityClassAnnotation: "shieldx-gold"},
+		}}
+		open := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		class := &schedulingv1.PriorityClass{
+			ObjectMeta:       metav1.ObjectMeta{Name: "shieldx-gold"},
+			Value:            1000,
+			PreemptionPolicy: ptr.To(corev1.PreemptNever),
+		}
+		defaulter = PodPriorityDefaulter{
+			Client: clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(gold, open, class).Build(),
+		}
+	})
+
+	It("Should give pods the PriorityClass of their tier", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme"},
+			Spec:       corev1.PodSpec{Priority: ptr.To[int32](0)},
+		}
+		Expect(defaulter.Default(ctx, pod)).To(Succeed())
+		Expect(pod.Spec.PriorityClassName).To(Equal("shieldx-gold"))
+		Expect(pod.Spec.Priority).To(HaveValue(Equal(int32(1000))))
+		Expect(pod.Spec.PreemptionPolicy).To(HaveValue(Equal(corev1.PreemptNever)))
+	})
+
+	It("Should keep a priorityClassName the pod sets", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme"},
+			Spec:       corev1.PodSpec{PriorityClassName: "batch-low"},
+		}
+		Expect(defaulter.Default(ctx, pod)).To(Succeed())
+		Expect(pod.Spec.PriorityClassName).To(Equal("batch-low"))
+		Expect(pod.Spec.Priority).To(BeNil())
+	})
+
+	It("Should leave pods outside tier namespaces untouched", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+		Expect(defaulter.Default(ctx, pod)).To(Succeed())
+		Expect(pod.Spec.PriorityClassName).To(BeEmpty())
+	})
+})
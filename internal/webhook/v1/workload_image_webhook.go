@@ -0,0 +1,252 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var workloadimagelog = logf.Log.WithName("workload-image")
+
+// SetupWorkloadImageWebhookWithManager registers the image signature webhook
+// for Pods and every pod-template workload in the manager.
+func SetupWorkloadImageWebhookWithManager(
+	mgr ctrl.Manager,
+	verifier verifyimage.Verifier,
+	policies *imagepolicy.Resolver,
+) error {
+	validator := &WorkloadImageValidator{Client: mgr.GetClient(), Verifier: verifier, Policies: policies}
+	for _, obj := range []client.Object{
+		&corev1.Pod{},
+		&appsv1.Deployment{},
+		&appsv1.StatefulSet{},
+		&appsv1.DaemonSet{},
+		&batchv1.Job{},
+		&batchv1.CronJob{},
+	} {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(obj).WithValidator(validator).Complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods;pods/ephemeralcontainers,verbs=create;update,versions=v1,name=vpod-v1.kb.io,admissionReviewVersions=v1,timeoutSeconds=30
+// +kubebuilder:webhook:path=/validate-apps-v1-deployment,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps,resources=deployments,verbs=create;update,versions=v1,name=vdeployment-v1.kb.io,admissionReviewVersions=v1,timeoutSeconds=30
+// +kubebuilder:webhook:path=/validate-apps-v1-statefulset,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps,resources=statefulsets,verbs=create;update,versions=v1,name=vstatefulset-v1.kb.io,admissionReviewVersions=v1,timeoutSeconds=30
+// +kubebuilder:webhook:path=/validate-apps-v1-daemonset,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps,resources=daemonsets,verbs=create;update,versions=v1,name=vdaemonset-v1.kb.io,admissionReviewVersions=v1,timeoutSeconds=30
+// +kubebuilder:webhook:path=/validate-batch-v1-job,mutating=false,failurePolicy=fail,sideEffects=None,groups=batch,resources=jobs,verbs=create;update,versions=v1,name=vjob-v1.kb.io,admissionReviewVersions=v1,timeoutSeconds=30
+// +kubebuilder:webhook:path=/validate-batch-v1-cronjob,mutating=false,failurePolicy=fail,sideEffects=None,groups=batch,resources=cronjobs,verbs=create;update,versions=v1,name=vcronjob-v1.kb.io,admissionReviewVersions=v1,timeoutSeconds=30
+
+// WorkloadImageValidator rejects Pods and pod-template workloads whose images
+// fail cosign signature verification, in namespaces labeled
+// security.shieldx.io/policy=enforce. Images under an Audit policy are
+// admitted with a warning instead.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type WorkloadImageValidator struct {
+	// Client looks up the namespace label; every namespace is enforced when nil.
+	Client client.Reader
+	// Verifier checks image signatures against the policy Policies selects.
+	Verifier verifyimage.Verifier
+	Policies *imagepolicy.Resolver
+}
+
+var _ webhook.CustomValidator = &WorkloadImageValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *WorkloadImageValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator. Only images added by the
+// update are verified, so workloads admitted earlier can still be scaled or relabeled.
+func (v *WorkloadImageValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	return v.validate(ctx, newObj, oldObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator; deletions are always allowed.
+func (v *WorkloadImageValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *WorkloadImageValidator) validate(ctx context.Context, obj, oldObj runtime.Object) (admission.Warnings, error) {
+	w, err := podTemplateOf(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := requestNamespace(ctx, w.Object)
+	enforced, err := imagePolicyEnforced(ctx, v.Client, namespace)
+	if err != nil || !enforced {
+		return nil, err
+	}
+	if v.Verifier == nil {
+		return nil, fmt.Errorf("no image verifier configured")
+	}
+	policies, err := v.Policies.Policies(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	admitted := map[string]bool{}
+	if oldObj != nil {
+		if old, err := podTemplateOf(oldObj); err == nil {
+			for _, c := range containerImages(old.Spec, old.Path) {
+				admitted[c.Image] = true
+			}
+		}
+	}
+
+	results := map[string]error{}
+	var errs field.ErrorList
+	var warnings admission.Warnings
+	for _, c := range containerImages(w.Spec, w.Path) {
+		if admitted[c.Image] {
+			continue
+		}
+		decision, verr := policies.For(c.Image)
+		if verr == nil && decision.Exempt {
+			continue
+		}
+		if verr == nil {
+			var done bool
+			if verr, done = results[c.Image]; !done {
+				_, verr = v.Verifier.Verify(ctx, c.Image, decision.Policy)
+				results[c.Image] = verr
+			}
+		}
+		if verr == nil {
+			continue
+		}
+		msg := fmt.Sprintf("image %q of container %q failed signature verification: %v", c.Image, c.Name, verr)
+		if !decision.Enforced() {
+			warnings = append(warnings, c.Path.String()+": "+msg)
+			continue
+		}
+		errs = append(errs, field.Forbidden(c.Path, msg))
+	}
+	if len(errs) == 0 {
+		return warnings, nil
+	}
+
+	workloadimagelog.Info("Rejected unsigned images", "kind", w.Kind.Kind,
+		"namespace", namespace, "name", w.Object.GetName(), "denied", len(errs))
+	return warnings, apierrors.NewInvalid(w.Kind, w.Object.GetName(), errs)
+}
+
+// requestNamespace returns the namespace of obj, falling back to the
+// admission request for objects created without one.
+func requestNamespace(ctx context.Context, obj client.Object) string {
+	if ns := obj.GetNamespace(); ns != "" {
+		return ns
+	}
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		return req.Namespace
+	}
+	return ""
+}
+
+// imagePolicyEnforced reports whether namespace opted into image signature
+// enforcement. Every namespace is enforced when c is nil.
+func imagePolicyEnforced(ctx context.Context, c client.Reader, namespace string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+	}
+	return ns.Labels[platformv1alpha1.ImagePolicyLabel] == platformv1alpha1.ImagePolicyEnforce, nil
+}
+
+// podTemplate is the pod spec carried by a Pod or workload, with its field path.
+type podTemplate struct {
+	Object client.Object
+	Kind   schema.GroupKind
+	Spec   *corev1.PodSpec
+	Path   *field.Path
+}
+
+// podTemplateOf extracts the pod spec of a Pod or pod-template workload.
+func podTemplateOf(obj runtime.Object) (podTemplate, error) {
+	template := field.NewPath("spec", "template", "spec")
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return podTemplate{o, corev1.SchemeGroupVersion.WithKind("Pod").GroupKind(), &o.Spec, field.NewPath("spec")}, nil
+	case *appsv1.Deployment:
+		return podTemplate{o, appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind(), &o.Spec.Template.Spec, template}, nil
+	case *appsv1.StatefulSet:
+		return podTemplate{o, appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind(), &o.Spec.Template.Spec, template}, nil
+	case *appsv1.DaemonSet:
+		return podTemplate{o, appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind(), &o.Spec.Template.Spec, template}, nil
+	case *batchv1.Job:
+		return podTemplate{o, batchv1.SchemeGroupVersion.WithKind("Job").GroupKind(), &o.Spec.Template.Spec, template}, nil
+	case *batchv1.CronJob:
+		return podTemplate{o, batchv1.SchemeGroupVersion.WithKind("CronJob").GroupKind(),
+			&o.Spec.JobTemplate.Spec.Template.Spec, field.NewPath("spec", "jobTemplate", "spec", "template", "spec")}, nil
+	default:
+		return podTemplate{}, fmt.Errorf("expected a Pod or pod-template workload but got %T", obj)
+	}
+}
+
+// containerImage is one image reference in a pod spec.
+type containerImage struct {
+	Name  string
+	Image string
+	Path  *field.Path
+}
+
+// containerImages lists the images of init, regular and ephemeral containers.
+func containerImages(spec *corev1.PodSpec, path *field.Path) []containerImage {
+	var out []containerImage
+	for i, c := range spec.InitContainers {
+		out = append(out, containerImage{c.Name, c.Image, path.Child("initContainers").Index(i).Child("image")})
+	}
+	for i, c := range spec.Containers {
+		out = append(out, containerImage{c.Name, c.Image, path.Child("containers").Index(i).Child("image")})
+	}
+	for i, c := range spec.EphemeralContainers {
+		out = append(out, containerImage{c.Name, c.Image, path.Child("ephemeralContainers").Index(i).Child("image")})
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/imagepolicy"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage/fake"
+)
+
+var _ = Describe("Workload Image Webhook", func() {
+	var (
+		validator WorkloadImageValidator
+		verifier  *fake.Verifier
+	)
+
+	BeforeEach(func() {
+		verifier = &fake.Verifier{Digests: map[string]string{"registry.example.com/signed:1.0": ""}}
+		enforced := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "tenant-acme",
+			Labels: map[string]string{platformv1alpha1.ImagePolicyLabel: platformv1alpha1.ImagePolicyEnforce},
+		}}
+		open := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		validator = WorkloadImageValidator{
+			Client:   clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(enforced, open).Build(),
+			Verifier: verifier,
+			Policies: &imagepolicy.Resolver{Default: verifyimage.Policy{Name: "default"}},
+		}
+	})
+
+	pod := func(namespace string, images ...string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace}}
+		for i, image := range images {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: string(rune('a' + i)), Image: image})
+		}
+		return p
+	}
+
+	It("Should admit pods whose images are signed", func() {
+		_, err := validator.ValidateCreate(ctx, pod("tenant-acme", "registry.example.com/signed:1.0"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verifier.Calls()).To(ConsistOf("registry.example.com/signed:1.0"))
+	})
+
+	It("Should deny unsigned images with the container and reason", func() {
+		_, err := validator.ValidateCreate(ctx, pod("tenant-acme",
+			"registry.example.com/signed:1.0", "registry.example.com/unsigned:1.0"))
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.containers[1].image"))
+		Expect(err.Error()).To(ContainSubstring(`container "b"`))
+		Expect(err.Error()).To(ContainSubstring("no matching signatures"))
+	})
+
+	It("Should skip namespaces that do not enforce the policy", func() {
+		_, err := validator.ValidateCreate(ctx, pod("default", "registry.example.com/unsigned:1.0"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verifier.Calls()).To(BeEmpty())
+	})
+
+	It("Should check the pod template of CronJobs", func() {
+		cj := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "tenant-acme"}}
+		cj.Spec.JobTemplate.Spec.Template.Spec.InitContainers = []corev1.Container{
+			{Name: "init", Image: "registry.example.com/unsigned:1.0"},
+		}
+		_, err := validator.ValidateCreate(ctx, cj)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.jobTemplate.spec.template.spec.initContainers[0].image"))
+	})
+
+	It("Should warn instead of denying images under an audit policy", func() {
+		policyScheme := runtime.NewScheme()
+		Expect(platformv1alpha1.AddToScheme(policyScheme)).To(Succeed())
+		validator.Policies.Client = clientfake.NewClientBuilder().WithScheme(policyScheme).WithObjects(
+			&platformv1alpha1.TenantImagePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "tenant-acme"},
+				Spec: platformv1alpha1.ImagePolicySpec{
+					Images:     []string{"registry.example.com/*"},
+					Exemptions: []string{"registry.example.com/debug:*"},
+					Mode:       platformv1alpha1.ImagePolicyModeAudit,
+				},
+			}).Build()
+
+		warnings, err := validator.ValidateCreate(ctx, pod("tenant-acme",
+			"registry.example.com/unsigned:1.0", "registry.example.com/debug:1.0"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("spec.containers[0].image")))
+		Expect(verifier.Calls()).To(ConsistOf("registry.example.com/unsigned:1.0"))
+	})
+
+	It("Should only verify images added by an update", func() {
+		oldPod := pod("tenant-acme", "registry.example.com/unsigned:1.0")
+		newPod := oldPod.DeepCopy()
+		newPod.Labels = map[string]string{"app": "web"}
+		_, err := validator.ValidateUpdate(ctx, oldPod, newPod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verifier.Calls()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// defaultTier returns the TenantTier annotated as the cluster default, or nil.
+func (d *TenantCustomDefaulter) defaultTier(ctx context.Context) (*platformv1alpha1.TenantTier, error) {
+	var tiers platformv1alpha1.TenantTierList
+	if err := d.Client.List(ctx, &tiers); err != nil {
+		return nil, fmt.Errorf("failed to list TenantTiers: %w", err)
+	}
+	for i := range tiers.Items {
+		if tiers.Items[i].Annotations[platformv1alpha1.DefaultTierAnnotation] == "true" {
+			return &tiers.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveTier returns the tier named by the Tenant, the cluster default when
+// it names none, or nil when neither exists.
+func (d *TenantCustomDefaulter) resolveTier(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) (*platformv1alpha1.TenantTier, error) {
+	if d.Client == nil {
+		return nil, nil
+	}
+	if tenant.Spec.Tier == "" {
+		return d.defaultTier(ctx)
+	}
+
+	var tier platformv1alpha1.TenantTier
+	if err := d.Client.Get(ctx, client.ObjectKey{Name: tenant.Spec.Tier}, &tier); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Left for the validating webhook to report.
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get TenantTier %q: %w", tenant.Spec.Tier, err)
+	}
+	return &tier, nil
+}
+
+// previousTierQuota returns the quota of the tier an updated Tenant is moving
+// away from, or nil on create, when the tier is unchanged or no longer exists.
+func (d *TenantCustomDefaulter) previousTierQuota(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) (*platformv1alpha1.ResourceQuota, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.Operation != admissionv1.Update || d.Client == nil {
+		return nil, nil
+	}
+	var old platformv1alpha1.Tenant
+	if err := json.Unmarshal(req.OldObject.Raw, &old); err != nil {
+		return nil, fmt.Errorf("failed to decode the existing Tenant: %w", err)
+	}
+	if old.Spec.Tier == "" || old.Spec.Tier == tenant.Spec.Tier {
+		return nil, nil
+	}
+
+	var tier platformv1alpha1.TenantTier
+	if err := d.Client.Get(ctx, client.ObjectKey{Name: old.Spec.Tier}, &tier); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get TenantTier %q: %w", old.Spec.Tier, err)
+	}
+	return &tier.Spec.ResourceQuota, nil
+}
+
+// defaultQuota copies every quota field the Tenant leaves empty from the tier.
+// Fields still holding the value of the previous tier, if any, are taken from
+// the new tier too, so a Tenant moving tiers only keeps its own overrides.
+func defaultQuota(q *platformv1alpha1.ResourceQuota, tier platformv1alpha1.ResourceQuota, previous *platformv1alpha1.ResourceQuota) {
+	if previous == nil {
+		previous = &platformv1alpha1.ResourceQuota{}
+	}
+	for _, f := range []struct {
+		value    *string
+		fallback string
+		previous string
+	}{
+		{&q.RequestsCPU, tier.RequestsCPU, previous.RequestsCPU},
+		{&q.RequestsMemory, tier.RequestsMemory, previous.RequestsMemory},
+		{&q.LimitsCPU, tier.LimitsCPU, previous.LimitsCPU},
+		{&q.LimitsMemory, tier.LimitsMemory, previous.LimitsMemory},
+		{&q.RequestsStorage, tier.RequestsStorage, previous.RequestsStorage},
+		{&q.Pods, tier.Pods, previous.Pods},
+	} {
+		*f.value = strings.TrimSpace(*f.value)
+		if *f.value == "" || *f.value == strings.TrimSpace(f.previous) {
+			*f.value = f.fallback
+		}
+	}
+}
+
+// defaultPolicyTypes lists Ingress and/or Egress when the Tenant declares
+// rules of that kind but no policyTypes.
+func defaultPolicyTypes(np *platformv1alpha1.NetworkPolicy) {
+	if len(np.PolicyTypes) > 0 {
+		return
+	}
+	if len(np.Ingress) > 0 {
+		np.PolicyTypes = append(np.PolicyTypes, "Ingress")
+	}
+	if len(np.Egress) > 0 {
+		np.PolicyTypes = append(np.PolicyTypes, "Egress")
+	}
+}
+
+// normalizeIdentities trims, lowercases and dedupes identities, keeping their order.
+func normalizeIdentities(entries []string) []string {
+	if entries == nil {
+		return nil
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" || slices.Contains(out, e) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
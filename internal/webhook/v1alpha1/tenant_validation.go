@@ -0,0 +1,383 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
+	"github.com/shieldx-bot/shieldx-platform/internal/identity"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// supportedIsolations lists the spec.isolation values the controller implements.
+var supportedIsolations = []string{platformv1alpha1.IsolationNamespace}
+
+// supportedPolicyTypes lists the accepted spec.networkPolicy.policyTypes.
+var supportedPolicyTypes = []string{"Ingress", "Egress"}
+
+// validateTenant checks the whole spec and returns every problem found.
+// The tier must exist only when checkTier is set, so Tenants keep working
+// after their tier is removed.
+func (v *TenantCustomValidator) validateTenant(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	checkTier bool,
+) (field.ErrorList, error) {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+
+	if msgs := validation.IsDNS1123Label(tenantNamespace(tenant)); len(msgs) > 0 {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), tenant.Name,
+			fmt.Sprintf("namespace %q is not a valid DNS label: %s", tenantNamespace(tenant), strings.Join(msgs, "; "))))
+	}
+
+	tierErrs, err := v.validateTier(ctx, tenant, spec.Child("tier"), checkTier)
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, tierErrs...)
+
+	if !slices.Contains(supportedIsolations, tenant.Spec.Isolation) {
+		errs = append(errs, field.NotSupported(spec.Child("isolation"), tenant.Spec.Isolation, supportedIsolations))
+	}
+
+	errs = append(errs, validateIdentities(tenant, spec)...)
+	errs = append(errs, validateQuota(tenant.Spec.ResourceQuota, spec.Child("resourceQuota"))...)
+	errs = append(errs, validateNetworkPolicy(tenant.Spec.NetworkPolicy, spec.Child("networkPolicy"))...)
+	return errs, nil
+}
+
+// validateNamespaceOwner rejects a new Tenant whose namespace already exists
+// and is not labeled for it, e.g. because a Tenant of the same name in
+// another namespace owns it. Retained and unmanaged namespaces are accepted
+// when annotated for adoption by this Tenant.
+func (v *TenantCustomValidator) validateNamespaceOwner(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+) (field.ErrorList, error) {
+	if v.Client == nil {
+		return nil, nil
+	}
+
+	var ns corev1.Namespace
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: tenantNamespace(tenant)}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %q: %w", tenantNamespace(tenant), err)
+	}
+	name, namespace := ns.Labels[platformv1alpha1.TenantNameLabel], ns.Labels[platformv1alpha1.TenantNamespaceLabel]
+	adopt := ns.Annotations[platformv1alpha1.AdoptAnnotation] == tenant.Namespace+"/"+tenant.Name
+	path := field.NewPath("metadata", "name")
+	switch {
+	case name == tenant.Name && namespace == tenant.Namespace:
+		return nil, nil
+	case name == "" && namespace == "":
+		if adopt {
+			return nil, nil
+		}
+		return field.ErrorList{field.Forbidden(path,
+			fmt.Sprintf("namespace %s already exists and is not managed by a Tenant; annotate it %s=%s/%s to adopt it",
+				ns.Name, platformv1alpha1.AdoptAnnotation, tenant.Namespace, tenant.Name))}, nil
+	case ns.Labels[platformv1alpha1.RetainedLabel] == "true":
+		if adopt {
+			return nil, nil
+		}
+		return field.ErrorList{field.Forbidden(path,
+			fmt.Sprintf("namespace %s was retained from Tenant %s/%s; annotate it %s=%s/%s to adopt it",
+				ns.Name, namespace, name, platformv1alpha1.AdoptAnnotation, tenant.Namespace, tenant.Name))}, nil
+	default:
+		return field.ErrorList{field.Forbidden(path,
+			fmt.Sprintf("namespace %s belongs to Tenant %s/%s", ns.Name, namespace, name))}, nil
+	}
+}
+
+// validateTier requires a tier and, when checkTier is set, that a TenantTier of that name exists.
+func (v *TenantCustomValidator) validateTier(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	path *field.Path,
+	checkTier bool,
+) (field.ErrorList, error) {
+	name := strings.ToLower(strings.TrimSpace(tenant.Spec.Tier))
+	if name == "" {
+		return field.ErrorList{field.Required(path, "a TenantTier name is required")}, nil
+	}
+	if !checkTier || v.Client == nil {
+		return nil, nil
+	}
+
+	var tiers platformv1alpha1.TenantTierList
+	if err := v.Client.List(ctx, &tiers); err != nil {
+		return nil, fmt.Errorf("failed to list TenantTiers: %w", err)
+	}
+	known := make([]string, 0, len(tiers.Items))
+	for _, t := range tiers.Items {
+		if t.Name == name {
+			return nil, nil
+		}
+		known = append(known, t.Name)
+	}
+	return field.ErrorList{field.NotSupported(path, tenant.Spec.Tier, known)}, nil
+}
+
+// validateIdentities checks the syntax of owners, members and viewers.
+func validateIdentities(tenant *platformv1alpha1.Tenant, spec *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(tenant.Spec.Owners) == 0 {
+		errs = append(errs, field.Required(spec.Child("owners"), "at least one owner is required"))
+	}
+	for _, list := range []struct {
+		name    string
+		entries []string
+	}{
+		{"owners", tenant.Spec.Owners},
+		{"members", tenant.Spec.Members},
+		{"viewers", tenant.Spec.Viewers},
+	} {
+		for i, entry := range list.entries {
+			if _, err := identity.ParseSubject(entry, tenantNamespace(tenant)); err != nil {
+				errs = append(errs, field.Invalid(spec.Child(list.name).Index(i), entry, err.Error()))
+			}
+		}
+	}
+	return errs
+}
+
+// validateQuota requires every quantity to parse and requests not to exceed limits.
+func validateQuota(q platformv1alpha1.ResourceQuota, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	parse := func(name, value string) *resource.Quantity {
+		if strings.TrimSpace(value) == "" {
+			return nil
+		}
+		qty, err := resource.ParseQuantity(strings.TrimSpace(value))
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Child(name), value, "must be a resource quantity such as 500m or 2Gi"))
+			return nil
+		}
+		return &qty
+	}
+
+	requestsCPU := parse("requestsCPU", q.RequestsCPU)
+	limitsCPU := parse("limitsCPU", q.LimitsCPU)
+	requestsMemory := parse("requestsMemory", q.RequestsMemory)
+	limitsMemory := parse("limitsMemory", q.LimitsMemory)
+	parse("requestsStorage", q.RequestsStorage)
+	parse("pods", q.Pods)
+
+	if requestsCPU != nil && limitsCPU != nil && requestsCPU.Cmp(*limitsCPU) > 0 {
+		errs = append(errs, field.Invalid(path.Child("requestsCPU"), q.RequestsCPU,
+			fmt.Sprintf("must not exceed limitsCPU (%s)", q.LimitsCPU)))
+	}
+	if requestsMemory != nil && limitsMemory != nil && requestsMemory.Cmp(*limitsMemory) > 0 {
+		errs = append(errs, field.Invalid(path.Child("requestsMemory"), q.RequestsMemory,
+			fmt.Sprintf("must not exceed limitsMemory (%s)", q.LimitsMemory)))
+	}
+	return errs
+}
+
+// validateNetworkPolicy checks policy types, peers and ports.
+func validateNetworkPolicy(np platformv1alpha1.NetworkPolicy, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, t := range np.PolicyTypes {
+		if !slices.Contains(supportedPolicyTypes, t) {
+			errs = append(errs, field.NotSupported(path.Child("policyTypes").Index(i), t, supportedPolicyTypes))
+		}
+	}
+	for i, rule := range np.Ingress {
+		rulePath := path.Child("ingress").Index(i)
+		errs = append(errs, validatePeer(rule.From, rulePath.Child("from"))...)
+		errs = append(errs, validatePorts(rule.Ports, rulePath.Child("ports"))...)
+	}
+	for i, rule := range np.Egress {
+		rulePath := path.Child("egress").Index(i)
+		errs = append(errs, validatePeer(rule.To, rulePath.Child("to"))...)
+		errs = append(errs, validatePorts(rule.Ports, rulePath.Child("ports"))...)
+	}
+	return errs
+}
+
+func validatePeer(peer platformv1alpha1.NetworkPolicyPeer, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if peer.Tenant != "" && peer.Namespace != nil {
+		errs = append(errs, field.Forbidden(path.Child("tenant"), "cannot be combined with namespace"))
+	}
+	if peer.IPBlock == nil {
+		return errs
+	}
+
+	if peer.Pod != nil || peer.Namespace != nil || peer.Tenant != "" {
+		errs = append(errs, field.Forbidden(path.Child("ipBlock"), "cannot be combined with pod, namespace or tenant"))
+	}
+	_, cidr, err := net.ParseCIDR(peer.IPBlock.CIDR)
+	if err != nil {
+		return append(errs, field.Invalid(path.Child("ipBlock", "cidr"), peer.IPBlock.CIDR, "must be a CIDR such as 10.0.0.0/8"))
+	}
+	for i, except := range peer.IPBlock.Except {
+		ip, _, err := net.ParseCIDR(except)
+		if err != nil || !cidr.Contains(ip) {
+			errs = append(errs, field.Invalid(path.Child("ipBlock", "except").Index(i), except,
+				fmt.Sprintf("must be a CIDR inside %s", peer.IPBlock.CIDR)))
+		}
+	}
+	return errs
+}
+
+func validatePorts(ports []platformv1alpha1.NetworkPolicyPort, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, p := range ports {
+		if p.EndPort != 0 && p.EndPort < p.Port {
+			errs = append(errs, field.Invalid(path.Index(i).Child("endPort"), p.EndPort,
+				fmt.Sprintf("must not be lower than port (%d)", p.Port)))
+		}
+	}
+	return errs
+}
+
+// quotaName is the ResourceQuota the controller keeps in the tenant namespace.
+const quotaName = "tenant-quota"
+
+// quotaField ties a spec.resourceQuota field to the resource it limits.
+type quotaField struct {
+	json     string
+	resource corev1.ResourceName
+	value    func(platformv1alpha1.ResourceQuota) string
+}
+
+var quotaFields = []quotaField{
+	{"requestsCPU", corev1.ResourceRequestsCPU, func(q platformv1alpha1.ResourceQuota) string { return q.RequestsCPU }},
+	{"requestsMemory", corev1.ResourceRequestsMemory, func(q platformv1alpha1.ResourceQuota) string { return q.RequestsMemory }},
+	{"limitsCPU", corev1.ResourceLimitsCPU, func(q platformv1alpha1.ResourceQuota) string { return q.LimitsCPU }},
+	{"limitsMemory", corev1.ResourceLimitsMemory, func(q platformv1alpha1.ResourceQuota) string { return q.LimitsMemory }},
+	{"requestsStorage", corev1.ResourceRequestsStorage, func(q platformv1alpha1.ResourceQuota) string { return q.RequestsStorage }},
+	{"pods", corev1.ResourcePods, func(q platformv1alpha1.ResourceQuota) string { return q.Pods }},
+}
+
+// validateTransition rejects changes to immutable fields and quotas below
+// current usage, and warns about risky changes that are still allowed.
+func (v *TenantCustomValidator) validateTransition(
+	ctx context.Context,
+	oldTenant, tenant *platformv1alpha1.Tenant,
+) (field.ErrorList, admission.Warnings, error) {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+	var warnings admission.Warnings
+
+	if oldTenant.Spec.Isolation != "" && tenant.Spec.Isolation != oldTenant.Spec.Isolation {
+		errs = append(errs, field.Invalid(spec.Child("isolation"), tenant.Spec.Isolation, "field is immutable"))
+	}
+
+	if !equality.Semantic.DeepEqual(oldTenant.Spec.ResourceQuota, tenant.Spec.ResourceQuota) ||
+		!strings.EqualFold(oldTenant.Spec.Tier, tenant.Spec.Tier) {
+		quotaErrs, err := v.validateQuotaUsage(ctx, tenant, spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		errs = append(errs, quotaErrs...)
+	}
+
+	if hasGroup(oldTenant.Spec.Owners) && !hasGroup(tenant.Spec.Owners) {
+		warnings = append(warnings, "spec.owners no longer lists a group; admin access now depends on individual users")
+	}
+	if oldTenant.Spec.DeletionPolicy == platformv1alpha1.DeletionPolicyProtect &&
+		tenant.Spec.DeletionPolicy != platformv1alpha1.DeletionPolicyProtect {
+		warnings = append(warnings, fmt.Sprintf("tenant %q is no longer protected against deletion", tenant.Name))
+	}
+	return errs, warnings, nil
+}
+
+// validateQuotaUsage rejects a tier or quota whose effective hard limits fall
+// below what the tenant namespace already uses.
+func (v *TenantCustomValidator) validateQuotaUsage(
+	ctx context.Context,
+	tenant *platformv1alpha1.Tenant,
+	spec *field.Path,
+) (field.ErrorList, error) {
+	if v.Client == nil {
+		return nil, nil
+	}
+
+	var quota corev1.ResourceQuota
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: tenantNamespace(tenant), Name: quotaName}, &quota); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ResourceQuota of tenant %q: %w", tenant.Name, err)
+	}
+
+	var tierQuota platformv1alpha1.ResourceQuota
+	var tier platformv1alpha1.TenantTier
+	err := v.Client.Get(ctx, client.ObjectKey{Name: strings.ToLower(strings.TrimSpace(tenant.Spec.Tier))}, &tier)
+	switch {
+	case err == nil:
+		tierQuota = tier.Spec.ResourceQuota
+	case !apierrors.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get TenantTier %q: %w", tenant.Spec.Tier, err)
+	}
+
+	var errs field.ErrorList
+	for _, f := range quotaFields {
+		path := spec.Child("resourceQuota", f.json)
+		value := strings.TrimSpace(f.value(tenant.Spec.ResourceQuota))
+		if value == "" {
+			path, value = spec.Child("tier"), strings.TrimSpace(f.value(tierQuota))
+		}
+		used, tracked := quota.Status.Used[f.resource]
+		if value == "" || !tracked {
+			continue
+		}
+		hard, err := resource.ParseQuantity(value)
+		if err != nil {
+			continue // reported by validateQuota
+		}
+		if hard.Cmp(used) < 0 {
+			errs = append(errs, field.Forbidden(path, fmt.Sprintf("%s would be limited to %s but namespace %s already uses %s",
+				f.resource, hard.String(), tenantNamespace(tenant), used.String())))
+		}
+	}
+	return errs, nil
+}
+
+func hasGroup(entries []string) bool {
+	for _, e := range entries {
+		if strings.HasPrefix(strings.TrimSpace(e), identity.GroupPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidTenant wraps validation problems in the error returned to the API server.
+func invalidTenant(tenant *platformv1alpha1.Tenant, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(platformv1alpha1.GroupVersion.WithKind("Tenant").GroupKind(), tenant.Name, errs)
+}
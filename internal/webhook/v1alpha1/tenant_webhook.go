@@ -19,11 +19,14 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
 	"github.com/shieldx-bot/shieldx-platform/internal/webhook/notify"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -36,8 +39,8 @@ var tenantlog = logf.Log.WithName("tenant-resource")
 // SetupTenantWebhookWithManager registers the webhook for Tenant in the manager.
 func SetupTenantWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&platformv1alpha1.Tenant{}).
-		WithValidator(&TenantCustomValidator{}).
-		WithDefaulter(&TenantCustomDefaulter{}).
+		WithValidator(&TenantCustomValidator{Client: mgr.GetClient()}).
+		WithDefaulter(&TenantCustomDefaulter{Client: mgr.GetClient()}).
 		Complete()
 }
 
@@ -51,22 +54,57 @@ func SetupTenantWebhookWithManager(mgr ctrl.Manager) error {
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as it is used only for temporary operations and does not need to be deeply copied.
 type TenantCustomDefaulter struct {
-	// TODO(user): Add more fields as needed for defaulting
+	// Client looks up TenantTiers; tier-based defaults are skipped when nil.
+	Client client.Reader
 }
 
 var _ webhook.CustomDefaulter = &TenantCustomDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Tenant.
-func (d *TenantCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *TenantCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	tenant, ok := obj.(*platformv1alpha1.Tenant)
 
 	if !ok {
 		return fmt.Errorf("expected an Tenant object but got %T", obj)
 	}
 	tenantlog.Info("Defaulting for Tenant", "name", tenant.GetName())
+	if !tenant.DeletionTimestamp.IsZero() {
+		return nil
+	}
 
-	// TODO(user): fill in your defaulting logic.
+	// Tiers are cluster-scoped objects with lowercase names.
+	tenant.Spec.Tier = strings.ToLower(strings.TrimSpace(tenant.Spec.Tier))
+	tier, err := d.resolveTier(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	if tier != nil {
+		tenant.Spec.Tier = tier.Name
+		previous, err := d.previousTierQuota(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		defaultQuota(&tenant.Spec.ResourceQuota, tier.Spec.ResourceQuota, previous)
+	}
 
+	if tenant.Spec.Isolation == "" {
+		tenant.Spec.Isolation = platformv1alpha1.IsolationNamespace
+	}
+	if tenant.Spec.DeletionPolicy == "" {
+		tenant.Spec.DeletionPolicy = platformv1alpha1.DeletionPolicyDelete
+	}
+	defaultPolicyTypes(&tenant.Spec.NetworkPolicy)
+
+	tenant.Spec.Owners = normalizeIdentities(tenant.Spec.Owners)
+	tenant.Spec.Members = normalizeIdentities(tenant.Spec.Members)
+	tenant.Spec.Viewers = normalizeIdentities(tenant.Spec.Viewers)
+
+	if tenant.Spec.Tier != "" {
+		if tenant.Labels == nil {
+			tenant.Labels = map[string]string{}
+		}
+		tenant.Labels[platformv1alpha1.TierLabel] = tenant.Spec.Tier
+	}
 	return nil
 }
 
@@ -80,13 +118,14 @@ func (d *TenantCustomDefaulter) Default(_ context.Context, obj runtime.Object) e
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type TenantCustomValidator struct {
-	// TODO(user): Add more fields as needed for validation
+	// Client looks up the tenant namespace; deletion checks that need it are skipped when nil.
+	Client client.Reader
 }
 
 var _ webhook.CustomValidator = &TenantCustomValidator{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Tenant.
-func (v *TenantCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *TenantCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	tenant, ok := obj.(*platformv1alpha1.Tenant)
 	tenantlog.Info("Webhook đã được gọi khi tạo Tenant")
 	if !ok {
@@ -106,22 +145,44 @@ func (v *TenantCustomValidator) ValidateCreate(_ context.Context, obj runtime.Ob
 
 	tenantlog.Info("Validation for Tenant upon creation", "name", tenant.GetName())
 
-	// TODO(user): fill in your validation logic upon object creation.
-
-	return nil, nil
+	errs, err := v.validateTenant(ctx, tenant, true)
+	if err != nil {
+		return nil, err
+	}
+	ownerErrs, err := v.validateNamespaceOwner(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return nil, invalidTenant(tenant, append(errs, ownerErrs...))
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Tenant.
-func (v *TenantCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *TenantCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	tenant, ok := newObj.(*platformv1alpha1.Tenant)
 	if !ok {
 		return nil, fmt.Errorf("expected a Tenant object for the newObj but got %T", newObj)
 	}
+	oldTenant, ok := oldObj.(*platformv1alpha1.Tenant)
+	if !ok {
+		return nil, fmt.Errorf("expected a Tenant object for the oldObj but got %T", oldObj)
+	}
 	tenantlog.Info("Validation for Tenant upon update", "name", tenant.GetName())
 
-	// TODO(user): fill in your validation logic upon object update.
+	// A Tenant being deleted only loses finalizers; never block its teardown.
+	if !tenant.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
 
-	return nil, nil
+	tierChanged := !strings.EqualFold(strings.TrimSpace(oldTenant.Spec.Tier), strings.TrimSpace(tenant.Spec.Tier))
+	errs, err := v.validateTenant(ctx, tenant, tierChanged)
+	if err != nil {
+		return nil, err
+	}
+	transitionErrs, warnings, err := v.validateTransition(ctx, oldTenant, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return warnings, invalidTenant(tenant, append(errs, transitionErrs...))
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Tenant.
@@ -130,19 +191,59 @@ func (v *TenantCustomValidator) ValidateDelete(ctx context.Context, obj runtime.
 	if !ok {
 		return nil, fmt.Errorf("expected a Tenant object but got %T", obj)
 	}
+	tenantlog.Info("Validation for Tenant upon deletion", "name", tenant.GetName())
+
+	if tenant.Spec.DeletionPolicy == platformv1alpha1.DeletionPolicyProtect {
+		return nil, fmt.Errorf("tenant %q has deletionPolicy %s; change it to %s or %s before deleting",
+			tenant.Name, platformv1alpha1.DeletionPolicyProtect,
+			platformv1alpha1.DeletionPolicyDelete, platformv1alpha1.DeletionPolicyRetain)
+	}
+
+	if tenant.Annotations[platformv1alpha1.ConfirmDeleteAnnotation] != tenant.Name {
+		running, err := v.runningPods(ctx, tenantNamespace(tenant))
+		if err != nil {
+			return nil, err
+		}
+		if running > 0 {
+			return nil, fmt.Errorf("tenant %q still runs %d pod(s) in namespace %s; annotate it with %s=%s to confirm the deletion",
+				tenant.Name, running, tenantNamespace(tenant), platformv1alpha1.ConfirmDeleteAnnotation, tenant.Name)
+		}
+	}
+
+	var warnings admission.Warnings
+	if tenant.Spec.DeletionPolicy == platformv1alpha1.DeletionPolicyRetain {
+		warnings = append(warnings, fmt.Sprintf("namespace %s is retained; recreate Tenant %s/%s to adopt it again",
+			tenantNamespace(tenant), tenant.Namespace, tenant.Name))
+	}
+
 	err1 := notify.SendMessageTelegram("Xóa Tenant: " + tenant.GetName())
 	if err1 != nil {
 		// Don't block the admission request if Telegram is down/misconfigured.
 		tenantlog.Error(err1, "failed to send Telegram notification", "tenant", tenant.GetName())
 	}
-	// err := k8s.DeleleteReconciliation(tenant.GetName())
-	// if err != nil {
-	// 	tenantlog.Error(err, "failed to delete reconciliation", "tenant", tenant.GetName())
-	// 	return nil, err
-	// }
-	tenantlog.Info("Validation for Tenant upon deletion", "name", tenant.GetName())
 
-	// TODO(user): fill in your validation logic upon object deletion.
+	return warnings, nil
+}
+
+// tenantNamespace is the namespace the controller provisions for the tenant.
+func tenantNamespace(tenant *platformv1alpha1.Tenant) string {
+	return "tenant-" + tenant.Name
+}
 
-	return nil, nil
+// runningPods counts the pods of namespace that have not finished.
+func (v *TenantCustomValidator) runningPods(ctx context.Context, namespace string) (int, error) {
+	if v.Client == nil {
+		return 0, nil
+	}
+	var pods corev1.PodList
+	if err := v.Client.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+	running := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			running++
+		}
+	}
+	return running, nil
 }
@@ -17,9 +17,21 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
 	platformv1alpha1 "github.com/shieldx-bot/shieldx-platform/api/v1alpha1"
 	// TODO (user): Add any additional imports if needed
 )
@@ -59,6 +71,64 @@ var _ = Describe("Tenant Webhook", func() {
 		//     By("checking that the default values are set")
 		//     Expect(obj.SomeFieldWithDefault).To(Equal("default_value"))
 		// })
+
+		BeforeEach(func() {
+			defaulter.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				&platformv1alpha1.TenantTier{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "bronze",
+						Annotations: map[string]string{platformv1alpha1.DefaultTierAnnotation: "true"},
+					},
+					Spec: platformv1alpha1.TenantTierSpec{
+						ResourceQuota: platformv1alpha1.ResourceQuota{RequestsCPU: "2", Pods: "10"},
+					},
+				},
+				&platformv1alpha1.TenantTier{
+					ObjectMeta: metav1.ObjectMeta{Name: "gold"},
+					Spec: platformv1alpha1.TenantTierSpec{
+						ResourceQuota: platformv1alpha1.ResourceQuota{RequestsCPU: "16", Pods: "100"},
+					},
+				},
+			).Build()
+		})
+
+		It("Should fill the default tier, isolation and quota", func() {
+			obj.Spec.Owners = []string{" Alice@Example.com", "alice@example.com", "group:Devs"}
+			obj.Spec.ResourceQuota.Pods = "5"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.Tier).To(Equal("bronze"))
+			Expect(obj.Labels).To(HaveKeyWithValue(platformv1alpha1.TierLabel, "bronze"))
+			Expect(obj.Spec.Isolation).To(Equal(platformv1alpha1.IsolationNamespace))
+			Expect(obj.Spec.DeletionPolicy).To(Equal(platformv1alpha1.DeletionPolicyDelete))
+			Expect(obj.Spec.ResourceQuota.RequestsCPU).To(Equal("2"))
+			Expect(obj.Spec.ResourceQuota.Pods).To(Equal("5"))
+			Expect(obj.Spec.Owners).To(Equal([]string{"alice@example.com", "group:devs"}))
+		})
+
+		It("Should take quota defaults from the named tier", func() {
+			obj.Spec.Tier = "Gold"
+			obj.Spec.ResourceQuota.LimitsCPU = "32"
+			obj.Spec.NetworkPolicy.Egress = []platformv1alpha1.NetworkPolicyEgressRule{{}}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.Tier).To(Equal("gold"))
+			Expect(obj.Spec.ResourceQuota).To(Equal(platformv1alpha1.ResourceQuota{RequestsCPU: "16", LimitsCPU: "32", Pods: "100"}))
+			Expect(obj.Spec.NetworkPolicy.PolicyTypes).To(Equal([]string{"Egress"}))
+
+			By("moving the Tenant to another tier")
+			oldObj = obj.DeepCopy()
+			raw, err := json.Marshal(oldObj)
+			Expect(err).NotTo(HaveOccurred())
+			update := admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				OldObject: runtime.RawExtension{Raw: raw},
+			}})
+			obj.Spec.Tier = "bronze"
+			Expect(defaulter.Default(update, obj)).To(Succeed())
+			Expect(obj.Spec.ResourceQuota).To(Equal(platformv1alpha1.ResourceQuota{RequestsCPU: "2", LimitsCPU: "32", Pods: "10"}),
+				"inherited values follow the tier, overrides are kept")
+		})
 	})
 
 	Context("When creating or updating Tenant under Validating Webhook", func() {
@@ -82,6 +152,161 @@ var _ = Describe("Tenant Webhook", func() {
 		//     obj.SomeRequiredField = "updated_value"
 		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
 		// })
+
+		BeforeEach(func() {
+			obj.Name = "acme"
+			obj.Spec = platformv1alpha1.TenantSpec{
+				Owners:    []string{"owner@shieldx.io", "group:platform", "sa:ci/deployer"},
+				Tier:      "Silver",
+				Isolation: platformv1alpha1.IsolationNamespace,
+				ResourceQuota: platformv1alpha1.ResourceQuota{
+					RequestsCPU: "500m",
+					LimitsCPU:   "2",
+				},
+			}
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				&platformv1alpha1.TenantTier{ObjectMeta: metav1.ObjectMeta{Name: "silver"}},
+			).Build()
+		})
+
+		It("Should admit a valid Tenant", func() {
+			errs, err := validator.validateTenant(ctx, obj, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should report every problem at once", func() {
+			obj.Name = strings.Repeat("a", 60)
+			obj.Spec.Tier = "platinum"
+			obj.Spec.Isolation = "shared"
+			obj.Spec.Owners = []string{"group:", "sa:Bad_Name"}
+			obj.Spec.ResourceQuota = platformv1alpha1.ResourceQuota{RequestsCPU: "4", LimitsCPU: "2", Pods: "many"}
+			obj.Spec.NetworkPolicy.PolicyTypes = []string{"Both"}
+
+			errs, err := validator.validateTenant(ctx, obj, true)
+			Expect(err).NotTo(HaveOccurred())
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			Expect(fields).To(ConsistOf(
+				"metadata.name",
+				"spec.tier",
+				"spec.isolation",
+				"spec.owners[0]",
+				"spec.owners[1]",
+				"spec.resourceQuota.pods",
+				"spec.resourceQuota.requestsCPU",
+				"spec.networkPolicy.policyTypes[0]",
+			))
+			Expect(invalidTenant(obj, errs)).To(MatchError(ContainSubstring("spec.tier")))
+		})
+
+		It("Should reject a name whose namespace belongs to another Tenant unless it is retained for adoption", func() {
+			obj.Namespace = "team-a"
+			namespace := func(labels map[string]string) *corev1.Namespace {
+				return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme", Labels: labels}}
+			}
+			adoptable := func(ns *corev1.Namespace) *corev1.Namespace {
+				ns.Annotations = map[string]string{platformv1alpha1.AdoptAnnotation: "team-a/acme"}
+				return ns
+			}
+			for _, tc := range []struct {
+				ns      *corev1.Namespace
+				allowed bool
+			}{
+				{namespace(map[string]string{"tenant": "acme", "platform.shieldx.io/tenant-namespace": "team-a"}), true},
+				{namespace(map[string]string{"tenant": "acme", "platform.shieldx.io/tenant-namespace": "team-b"}), false},
+				{namespace(nil), false},
+				{namespace(map[string]string{
+					"tenant": "acme", "platform.shieldx.io/tenant-namespace": "team-b", "platform.shieldx.io/retained": "true",
+				}), false},
+				{adoptable(namespace(map[string]string{
+					"tenant": "acme", "platform.shieldx.io/tenant-namespace": "team-b", "platform.shieldx.io/retained": "true",
+				})), true},
+				{adoptable(namespace(map[string]string{"tenant": "acme", "platform.shieldx.io/tenant-namespace": "team-b"})), false},
+			} {
+				validator.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+					&platformv1alpha1.TenantTier{ObjectMeta: metav1.ObjectMeta{Name: "silver"}}, tc.ns,
+				).Build()
+				_, err := validator.ValidateCreate(ctx, obj)
+				if tc.allowed {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError(ContainSubstring("namespace tenant-acme")))
+				}
+			}
+		})
+
+		It("Should not require the tier to exist when it is unchanged on update", func() {
+			obj.Spec.Tier = "retired"
+			oldObj = obj.DeepCopy()
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Tier = "platinum"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should reject changing isolation", func() {
+			oldObj = obj.DeepCopy()
+			obj.Spec.Isolation = "cluster"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("field is immutable")))
+		})
+
+		It("Should reject a quota below current usage and warn about risky changes", func() {
+			By("reporting usage in the tenant namespace")
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				&platformv1alpha1.TenantTier{ObjectMeta: metav1.ObjectMeta{Name: "silver"}},
+				&corev1.ResourceQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: "tenant-quota", Namespace: "tenant-acme"},
+					Status: corev1.ResourceQuotaStatus{Used: corev1.ResourceList{
+						corev1.ResourceRequestsCPU: resource.MustParse("400m"),
+					}},
+				},
+			).Build()
+			oldObj = obj.DeepCopy()
+
+			obj.Spec.ResourceQuota.RequestsCPU = "300m"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.resourceQuota.requestsCPU")))
+
+			By("dropping the owner group while keeping enough quota")
+			obj.Spec.ResourceQuota.RequestsCPU = "400m"
+			obj.Spec.Owners = []string{"owner@shieldx.io"}
+			warnings, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("no longer lists a group")))
+		})
+	})
+
+	Context("When deleting Tenant under Validating Webhook", func() {
+		BeforeEach(func() {
+			obj.Name = "acme"
+		})
+
+		It("Should deny deletion when deletionPolicy is Protect", func() {
+			obj.Spec.DeletionPolicy = platformv1alpha1.DeletionPolicyProtect
+			obj.Annotations = map[string]string{platformv1alpha1.ConfirmDeleteAnnotation: "acme"}
+			_, err := validator.ValidateDelete(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("deletionPolicy Protect")))
+		})
+
+		It("Should require confirmation while the namespace still runs pods", func() {
+			By("running a pod in the tenant namespace")
+			validator.Client = fake.NewClientBuilder().WithObjects(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "tenant-acme"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			}).Build()
+
+			_, err := validator.ValidateDelete(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("platform.shieldx.io/confirm-delete=acme")))
+
+			By("confirming with the wrong name")
+			obj.Annotations = map[string]string{platformv1alpha1.ConfirmDeleteAnnotation: "other"}
+			_, err = validator.ValidateDelete(ctx, obj)
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 })
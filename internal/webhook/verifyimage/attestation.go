@@ -0,0 +1,254 @@
+package verifyimage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+// Predicate types of the attestations most CI pipelines attach.
+const (
+	PredicateSLSAProvenance02 = "https://slsa.dev/provenance/v0.2"
+	PredicateSLSAProvenance1  = "https://slsa.dev/provenance/v1"
+	PredicateCycloneDX        = "https://cyclonedx.org/bom"
+	PredicateSPDX             = "https://spdx.dev/Document"
+)
+
+// predicateAliases maps the short names accepted by cosign attest --type.
+var predicateAliases = map[string]string{
+	"slsaprovenance":   PredicateSLSAProvenance02,
+	"slsaprovenance02": PredicateSLSAProvenance02,
+	"slsaprovenance1":  PredicateSLSAProvenance1,
+	"cyclonedx":        PredicateCycloneDX,
+	"spdx":             PredicateSPDX,
+	"spdxjson":         PredicateSPDX,
+}
+
+// AttestationRule requires a signed in-toto attestation of PredicateType.
+// The attestation must be signed by the key or identity that signed the image.
+type AttestationRule struct {
+	// PredicateType is a predicate URI or a cosign short name such as
+	// slsaprovenance, slsaprovenance1, cyclonedx or spdxjson.
+	PredicateType string
+	// BuilderID, if set, is a glob the SLSA provenance builder ID must match.
+	BuilderID string
+	// SourceRepo, if set, is a glob the provenance source repository must
+	// match, without scheme or ref, e.g. github.com/shieldx-bot/*.
+	SourceRepo string
+}
+
+// predicateURI resolves the short name of the rule's predicate type.
+func (r AttestationRule) predicateURI() string {
+	if uri, ok := predicateAliases[r.PredicateType]; ok {
+		return uri
+	}
+	return r.PredicateType
+}
+
+// Validate checks the predicate type and that provenance constraints are
+// only set on provenance rules.
+func (r AttestationRule) Validate() error {
+	uri := r.predicateURI()
+	if u, err := url.ParseRequestURI(uri); err != nil || u.Scheme == "" {
+		return fmt.Errorf("invalid predicate type %q", r.PredicateType)
+	}
+	if (r.BuilderID != "" || r.SourceRepo != "") && !isProvenance(uri) {
+		return fmt.Errorf("builder and source constraints need a SLSA provenance predicate, got %q", r.PredicateType)
+	}
+	return nil
+}
+
+func isProvenance(uri string) bool {
+	return uri == PredicateSLSAProvenance02 || uri == PredicateSLSAProvenance1
+}
+
+// ParseAttestationRules parses ";"-separated rules of the form
+// "<type>[,builder=<glob>][,source=<glob>]", e.g.
+// "slsaprovenance,source=github.com/shieldx-bot/*;cyclonedx".
+func ParseAttestationRules(spec string) ([]AttestationRule, error) {
+	var rules []AttestationRule
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ",")
+		rule := AttestationRule{PredicateType: strings.TrimSpace(fields[0])}
+		for _, f := range fields[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(f), "=")
+			switch key {
+			case "builder":
+				rule.BuilderID = value
+			case "source":
+				rule.SourceRepo = value
+			default:
+				return nil, fmt.Errorf("invalid attestation rule %q: unknown constraint %q", item, key)
+			}
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid attestation rule %q: %w", item, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// statement is the part of an in-toto statement the rules look at.
+type statement struct {
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// provenance covers the builder and source fields of SLSA v0.2 and v1.
+type provenance struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	Invocation struct {
+		ConfigSource struct {
+			URI string `json:"uri"`
+		} `json:"configSource"`
+	} `json:"invocation"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+	} `json:"runDetails"`
+	BuildDefinition struct {
+		ExternalParameters struct {
+			Workflow struct {
+				Repository string `json:"repository"`
+			} `json:"workflow"`
+		} `json:"externalParameters"`
+		ResolvedDependencies []struct {
+			URI string `json:"uri"`
+		} `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+}
+
+func (p provenance) builderID() string {
+	if p.RunDetails.Builder.ID != "" {
+		return p.RunDetails.Builder.ID
+	}
+	return p.Builder.ID
+}
+
+func (p provenance) sourceRepo() string {
+	for _, uri := range []string{p.BuildDefinition.ExternalParameters.Workflow.Repository, p.Invocation.ConfigSource.URI} {
+		if uri != "" {
+			return normalizeSource(uri)
+		}
+	}
+	if deps := p.BuildDefinition.ResolvedDependencies; len(deps) > 0 {
+		return normalizeSource(deps[0].URI)
+	}
+	return ""
+}
+
+// normalizeSource turns git+https://github.com/org/repo.git@refs/heads/main
+// into github.com/org/repo.
+func normalizeSource(uri string) string {
+	uri = strings.TrimPrefix(uri, "git+")
+	if _, rest, ok := strings.Cut(uri, "://"); ok {
+		uri = rest
+	}
+	uri, _, _ = strings.Cut(uri, "@")
+	return strings.TrimSuffix(uri, ".git")
+}
+
+// satisfies reports why st does not satisfy r, or nil when it does.
+func (r AttestationRule) satisfies(st statement) error {
+	if r.BuilderID == "" && r.SourceRepo == "" {
+		return nil
+	}
+	var p provenance
+	if err := json.Unmarshal(st.Predicate, &p); err != nil {
+		return fmt.Errorf("decode provenance: %w", err)
+	}
+	if r.BuilderID != "" && !MatchGlob(r.BuilderID, p.builderID()) {
+		return fmt.Errorf("builder %q does not match %q", p.builderID(), r.BuilderID)
+	}
+	if r.SourceRepo != "" && !MatchGlob(r.SourceRepo, p.sourceRepo()) {
+		return fmt.Errorf("source %q does not match %q", p.sourceRepo(), r.SourceRepo)
+	}
+	return nil
+}
+
+// evaluateAttestations checks every rule against the verified statements and
+// returns the predicate types that satisfied them.
+func evaluateAttestations(statements []statement, rules []AttestationRule) ([]string, error) {
+	satisfied := make([]string, 0, len(rules))
+	var errs []error
+	for _, rule := range rules {
+		uri := rule.predicateURI()
+		var ruleErrs []error
+		ok := false
+		for _, st := range statements {
+			if st.PredicateType != uri {
+				continue
+			}
+			if err := rule.satisfies(st); err != nil {
+				ruleErrs = append(ruleErrs, err)
+				continue
+			}
+			ok = true
+			break
+		}
+		switch {
+		case ok:
+			satisfied = append(satisfied, uri)
+		case len(ruleErrs) == 0:
+			errs = append(errs, fmt.Errorf("no signed %s attestation", uri))
+		default:
+			errs = append(errs, fmt.Errorf("%s attestation: %w", uri, errors.Join(ruleErrs...)))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return satisfied, nil
+}
+
+// verifyAttestations checks the attestations of digest signed by the signer
+// co trusts against rules.
+func verifyAttestations(ctx context.Context, digest name.Digest, co *cosign.CheckOpts, rules []AttestationRule) ([]string, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	aco := *co
+	aco.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
+	atts, _, err := cosign.VerifyImageAttestations(ctx, digest, &aco)
+	if err != nil {
+		return nil, fmt.Errorf("attestations: %w", classify(err))
+	}
+	statements := make([]statement, 0, len(atts))
+	for _, att := range atts {
+		payload, err := att.Payload()
+		if err != nil {
+			continue
+		}
+		var env struct {
+			Payload string `json:"payload"`
+		}
+		if err := json.Unmarshal(payload, &env); err != nil {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil {
+			continue
+		}
+		var st statement
+		if err := json.Unmarshal(raw, &st); err != nil {
+			continue
+		}
+		statements = append(statements, st)
+	}
+	return evaluateAttestations(statements, rules)
+}
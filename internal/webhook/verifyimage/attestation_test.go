@@ -0,0 +1,72 @@
+package verifyimage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseAttestationRules(t *testing.T) {
+	rules, err := ParseAttestationRules("slsaprovenance1,builder=https://github.com/slsa-framework/*,source=github.com/shieldx-bot/*; cyclonedx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 || rules[0].predicateURI() != PredicateSLSAProvenance1 || rules[1].predicateURI() != PredicateCycloneDX {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+	if rules[0].SourceRepo != "github.com/shieldx-bot/*" {
+		t.Fatalf("unexpected source constraint %q", rules[0].SourceRepo)
+	}
+
+	for _, spec := range []string{"sbom", "cyclonedx,source=github.com/*", "slsaprovenance,owner=me"} {
+		if _, err := ParseAttestationRules(spec); err == nil {
+			t.Errorf("ParseAttestationRules(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestEvaluateAttestations(t *testing.T) {
+	provenance02 := statement{
+		PredicateType: PredicateSLSAProvenance02,
+		Predicate: json.RawMessage(`{
+			"builder": {"id": "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_container_slsa3.yml@refs/tags/v2.0.0"},
+			"invocation": {"configSource": {"uri": "git+https://github.com/shieldx-bot/shieldx-platform@refs/heads/main"}}
+		}`),
+	}
+	provenance1 := statement{
+		PredicateType: PredicateSLSAProvenance1,
+		Predicate: json.RawMessage(`{
+			"runDetails": {"builder": {"id": "https://github.com/actions/runner/github-hosted"}},
+			"buildDefinition": {"externalParameters": {"workflow": {"repository": "https://github.com/other/fork"}}}
+		}`),
+	}
+	sbom := statement{PredicateType: PredicateCycloneDX, Predicate: json.RawMessage(`{}`)}
+
+	for _, tc := range []struct {
+		name       string
+		statements []statement
+		rules      []AttestationRule
+		ok         bool
+	}{
+		{"provenance from our CI", []statement{provenance02}, []AttestationRule{{
+			PredicateType: "slsaprovenance",
+			BuilderID:     "https://github.com/slsa-framework/slsa-github-generator/*",
+			SourceRepo:    "github.com/shieldx-bot/*",
+		}}, true},
+		{"provenance from another repo", []statement{provenance1}, []AttestationRule{{
+			PredicateType: "slsaprovenance1",
+			SourceRepo:    "github.com/shieldx-bot/*",
+		}}, false},
+		{"sbom present", []statement{provenance02, sbom}, []AttestationRule{{PredicateType: "cyclonedx"}}, true},
+		{"sbom missing", []statement{provenance02}, []AttestationRule{{PredicateType: "cyclonedx"}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := evaluateAttestations(tc.statements, tc.rules)
+			if (err == nil) != tc.ok {
+				t.Fatalf("evaluateAttestations() = %v, %v, want ok=%t", got, err, tc.ok)
+			}
+			if tc.ok && len(got) != len(tc.rules) {
+				t.Fatalf("expected %d satisfied rules, got %v", len(tc.rules), got)
+			}
+		})
+	}
+}
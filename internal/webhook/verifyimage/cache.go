@@ -0,0 +1,187 @@
+package verifyimage
+
+import (
+	"container/list"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	cacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shieldx_image_verification_cache_hits_total",
+		Help: "Image verifications answered from the cache, by cached result.",
+	}, []string{"result"})
+	cacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "shieldx_image_verification_cache_misses_total",
+		Help: "Image verifications that had to contact the registry and transparency log.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheHits, cacheMisses)
+}
+
+// CacheOptions configures a verification Cache.
+type CacheOptions struct {
+	// PositiveTTL is how long a successful verification is reused.
+	PositiveTTL time.Duration
+	// NegativeTTL is how long a failed verification is reused; zero disables negative caching.
+	NegativeTTL time.Duration
+	// MaxEntries bounds the cache; the least recently used entry is evicted first.
+	MaxEntries int
+}
+
+type cacheEntry struct {
+	key     string
+	res     Result
+	err     error
+	expires time.Time
+}
+
+// Cache remembers verification results keyed by image digest and policy
+// fingerprint. It is safe for concurrent use, and concurrent misses on the
+// same key share a single verification.
+type Cache struct {
+	opts  CacheOptions
+	group singleflight.Group
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// NewCache returns an empty Cache.
+func NewCache(opts CacheOptions) *Cache {
+	return &Cache{
+		opts:    opts,
+		now:     time.Now,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+// Do returns the cached result for key, or runs verify once on behalf of all
+// concurrent callers and caches what it returns. Errors wrapped in
+// transientError are returned but not cached.
+func (c *Cache) Do(key string, verify func() (Result, error)) (Result, error) {
+	if e, ok := c.get(key); ok {
+		cacheHits.WithLabelValues(resultLabel(e.err)).Inc()
+		return e.res, e.err
+	}
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		if e, ok := c.get(key); ok {
+			cacheHits.WithLabelValues(resultLabel(e.err)).Inc()
+			return e.res, e.err
+		}
+		cacheMisses.Inc()
+		res, err := verify()
+		c.put(key, res, err)
+		return res, err
+	})
+	res, _ := v.(Result)
+	return res, err
+}
+
+func (c *Cache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !c.now().Before(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e, true
+}
+
+func (c *Cache) put(key string, res Result, err error) {
+	ttl := c.opts.PositiveTTL
+	if err != nil {
+		ttl = c.opts.NegativeTTL
+	}
+	if IsTransient(err) {
+		return
+	}
+	if ttl <= 0 || c.opts.MaxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &cacheEntry{key: key, res: res, err: err, expires: c.now().Add(ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.opts.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// transientError marks a failure that says nothing about the image, such as
+// a registry outage or a policy without keys, so it is never cached.
+type transientError struct{ error }
+
+func (e transientError) Unwrap() error { return e.error }
+
+// Transient marks err as a failure to perform verification, e.g. in fakes.
+func Transient(err error) error {
+	return transientError{err}
+}
+
+// IsTransient reports whether err means verification could not be performed,
+// e.g. because the registry or Rekor was unreachable, rather than that the
+// image failed it.
+func IsTransient(err error) bool {
+	var t transientError
+	return errors.As(err, &t)
+}
+
+func resultLabel(err error) string {
+	if err != nil {
+		return "rejected"
+	}
+	return "verified"
+}
+
+// NewCacheFromEnv returns a Cache tuned with VERIFY_CACHE_POSITIVE_TTL,
+// VERIFY_CACHE_NEGATIVE_TTL and VERIFY_CACHE_SIZE. One cache should be shared
+// by the scanner and the admission webhooks.
+func NewCacheFromEnv() *Cache {
+	return NewCache(CacheOptions{
+		PositiveTTL: durationEnv("VERIFY_CACHE_POSITIVE_TTL", 10*time.Minute),
+		NegativeTTL: durationEnv("VERIFY_CACHE_NEGATIVE_TTL", time.Minute),
+		MaxEntries:  intEnv("VERIFY_CACHE_SIZE", 1024),
+	})
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return d
+	}
+	return def
+}
+
+func intEnv(key string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return n
+	}
+	return def
+}
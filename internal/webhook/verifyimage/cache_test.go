@@ -0,0 +1,115 @@
+package verifyimage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+)
+
+func TestCacheTTLs(t *testing.T) {
+	now := time.Now()
+	c := NewCache(CacheOptions{PositiveTTL: time.Minute, NegativeTTL: time.Second, MaxEntries: 10})
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	verify := func(err error) func() (Result, error) {
+		return func() (Result, error) { calls++; return Result{Key: "primary"}, err }
+	}
+	unsigned := errors.New("no matching signatures")
+
+	_, _ = c.Do("signed", verify(nil))
+	if res, _ := c.Do("signed", verify(nil)); res.Key != "primary" {
+		t.Fatalf("expected the cached result, got %+v", res)
+	}
+	if _, err := c.Do("unsigned", verify(unsigned)); !errors.Is(err, unsigned) {
+		t.Fatalf("expected the verification error, got %v", err)
+	}
+	if _, err := c.Do("unsigned", verify(nil)); !errors.Is(err, unsigned) {
+		t.Fatalf("expected the cached verification error, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 verifications, got %d", calls)
+	}
+
+	now = now.Add(2 * time.Second)
+	_, _ = c.Do("signed", verify(nil))
+	if _, err := c.Do("unsigned", verify(nil)); err != nil {
+		t.Fatalf("expected the negative entry to expire, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected only the negative entry to be re-verified, got %d verifications", calls)
+	}
+}
+
+func TestCacheSkipsTransientErrors(t *testing.T) {
+	c := NewCache(CacheOptions{PositiveTTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10})
+	calls := 0
+	for range 2 {
+		_, _ = c.Do("img", func() (Result, error) {
+			calls++
+			return Result{}, transientError{errors.New("registry unreachable")}
+		})
+	}
+	if calls != 2 {
+		t.Fatalf("expected transient errors not to be cached, got %d verifications", calls)
+	}
+}
+
+func TestClassifyOnlyTreatsVerdictsAsPermanent(t *testing.T) {
+	if IsTransient(classify(fmt.Errorf("key %q: %w", "ci", cosign.NewVerificationError("invalid signature")))) {
+		t.Error("a signature verdict must not be transient")
+	}
+	if !IsTransient(classify(errors.New("dial tcp: connection refused"))) {
+		t.Error("a network failure must be transient")
+	}
+	if !IsTransient(errors.Join(errors.New("no matching signatures"), Transient(errors.New("rekor unreachable")))) {
+		t.Error("a joined outage must keep the result transient")
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewCache(CacheOptions{PositiveTTL: time.Minute, MaxEntries: 2})
+	calls := 0
+	verify := func() (Result, error) { calls++; return Result{}, nil }
+
+	_, _ = c.Do("a", verify)
+	_, _ = c.Do("b", verify)
+	_, _ = c.Do("a", verify)
+	_, _ = c.Do("c", verify) // evicts b
+	_, _ = c.Do("a", verify)
+	_, _ = c.Do("b", verify)
+	if calls != 4 {
+		t.Fatalf("expected 4 verifications, got %d", calls)
+	}
+}
+
+func TestCacheDeduplicatesConcurrentMisses(t *testing.T) {
+	c := NewCache(CacheOptions{PositiveTTL: time.Minute, MaxEntries: 10})
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = c.Do("img", func() (Result, error) {
+				calls.Add(1)
+				<-release
+				return Result{}, nil
+			})
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected a single verification, got %d", n)
+	}
+}
@@ -0,0 +1,50 @@
+// Package fake provides an in-memory verifyimage.Verifier for tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+)
+
+// Verifier accepts the images listed in Digests and rejects every other one.
+type Verifier struct {
+	// Digests maps each signed image reference to the digest it resolves to;
+	// an empty digest returns the reference itself.
+	Digests map[string]string
+	// Errors overrides the error returned for specific image references.
+	Errors map[string]error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+var _ verifyimage.Verifier = &Verifier{}
+
+// Verify implements verifyimage.Verifier.
+func (f *Verifier) Verify(_ context.Context, imageRef string, policy verifyimage.Policy) (verifyimage.Result, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, imageRef)
+	f.mu.Unlock()
+
+	if err, ok := f.Errors[imageRef]; ok {
+		return verifyimage.Result{}, err
+	}
+	digest, ok := f.Digests[imageRef]
+	if !ok {
+		return verifyimage.Result{}, fmt.Errorf("verify failed for %q: no matching signatures", imageRef)
+	}
+	if digest == "" {
+		digest = imageRef
+	}
+	return verifyimage.Result{Digest: digest, Policy: policy.Name}, nil
+}
+
+// Calls returns the image references verified so far, in order.
+func (f *Verifier) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
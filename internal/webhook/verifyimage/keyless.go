@@ -0,0 +1,125 @@
+package verifyimage
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/sigstore-go/pkg/root"
+)
+
+// Identity is a certificate identity trusted for keyless signatures. Each of
+// issuer and subject is matched exactly, or by its regular expression when set.
+type Identity struct {
+	Issuer        string
+	IssuerRegExp  string
+	Subject       string
+	SubjectRegExp string
+}
+
+// Validate checks that the identity constrains both issuer and subject and
+// that its regular expressions compile.
+func (id Identity) Validate() error {
+	if id.Issuer == "" && id.IssuerRegExp == "" {
+		return fmt.Errorf("identity must set an issuer or issuer regexp")
+	}
+	if id.Subject == "" && id.SubjectRegExp == "" {
+		return fmt.Errorf("identity must set a subject or subject regexp")
+	}
+	for _, expr := range []string{id.IssuerRegExp, id.SubjectRegExp} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid identity regexp %q: %w", expr, err)
+		}
+	}
+	return nil
+}
+
+// LoadTrustedRoot reads a Sigstore trusted_root.json holding the Fulcio CAs,
+// Rekor and CT log keys, so keyless verification works without reaching TUF.
+func LoadTrustedRoot(path string) (root.TrustedMaterial, error) {
+	tr, err := root.NewTrustedRootFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("load trusted root %q: %w", path, err)
+	}
+	return tr, nil
+}
+
+// trustedRoot returns the configured trusted root, or the public Sigstore
+// root fetched once through TUF when none is configured.
+func (v *CosignVerifier) trustedRoot() (root.TrustedMaterial, error) {
+	if v.TrustedRoot != nil {
+		return v.TrustedRoot, nil
+	}
+	v.live.mu.Lock()
+	defer v.live.mu.Unlock()
+	if v.live.material == nil {
+		tr, err := cosign.TrustedRoot()
+		if err != nil {
+			return nil, transientError{fmt.Errorf("fetch Sigstore trusted root (set a local trusted root to verify offline): %w", err)}
+		}
+		v.live.material = tr
+	}
+	return v.live.material, nil
+}
+
+// verifyKeyless checks keyless signatures of digest against the identities of policy.
+func (v *CosignVerifier) verifyKeyless(ctx context.Context, digest name.Digest, policy Policy) (Result, error) {
+	trusted, err := v.trustedRoot()
+	if err != nil {
+		return Result{}, err
+	}
+	co := &cosign.CheckOpts{TrustedMaterial: trusted, Offline: v.Offline}
+	for _, id := range policy.Identities {
+		co.Identities = append(co.Identities, cosign.Identity{
+			Issuer:        id.Issuer,
+			IssuerRegExp:  id.IssuerRegExp,
+			Subject:       id.Subject,
+			SubjectRegExp: id.SubjectRegExp,
+		})
+	}
+	sigs, _, err := cosign.VerifyImageSignatures(ctx, digest, co)
+	if err != nil {
+		return Result{}, fmt.Errorf("keyless: %w", classify(err))
+	}
+	atts, err := verifyAttestations(ctx, digest, co, policy.Attestations)
+	if err != nil {
+		return Result{}, fmt.Errorf("keyless: %w", err)
+	}
+	return Result{Digest: digest.String(), Policy: policy.Name, Identity: signerIdentity(sigs), Attestations: atts}, nil
+}
+
+// signerIdentity returns the subject of the first signing certificate.
+func signerIdentity(sigs []oci.Signature) string {
+	for _, sig := range sigs {
+		cert, err := sig.Cert()
+		if err != nil || cert == nil {
+			continue
+		}
+		if s := certSubject(cert); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func certSubject(cert *x509.Certificate) string {
+	switch {
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	default:
+		return ""
+	}
+}
+
+// liveRoot caches the trusted root once it was fetched through TUF.
+type liveRoot struct {
+	mu       sync.Mutex
+	material root.TrustedMaterial
+}
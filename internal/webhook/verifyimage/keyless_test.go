@@ -0,0 +1,68 @@
+package verifyimage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIdentityValidate(t *testing.T) {
+	for _, tc := range []struct {
+		id    Identity
+		valid bool
+	}{
+		{Identity{Issuer: "https://token.actions.githubusercontent.com", SubjectRegExp: `^https://github\.com/shieldx-bot/.*$`}, true},
+		{Identity{IssuerRegExp: ".*", Subject: "ci@example.com"}, true},
+		{Identity{Subject: "ci@example.com"}, false},
+		{Identity{Issuer: "https://accounts.google.com"}, false},
+		{Identity{Issuer: "https://accounts.google.com", SubjectRegExp: "("}, false},
+	} {
+		if err := tc.id.Validate(); (err == nil) != tc.valid {
+			t.Errorf("Validate(%+v) = %v, want valid=%t", tc.id, err, tc.valid)
+		}
+	}
+}
+
+func TestPolicyFromEnvKeyless(t *testing.T) {
+	t.Setenv("COSIGN_PUB_KEY_PEM", "")
+	t.Setenv("COSIGN_KEYRING", "")
+	t.Setenv("COSIGN_CERTIFICATE_OIDC_ISSUER", "https://token.actions.githubusercontent.com")
+	t.Setenv("COSIGN_CERTIFICATE_IDENTITY_REGEXP", `^https://github\.com/shieldx-bot/.*$`)
+
+	policy, err := PolicyFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Identities) != 1 || len(policy.Keys) != 0 {
+		t.Fatalf("expected a keyless-only policy, got %+v", policy)
+	}
+
+	t.Setenv("COSIGN_CERTIFICATE_IDENTITY_REGEXP", "")
+	if _, err := PolicyFromEnv(); err == nil {
+		t.Fatal("expected an identity without a subject to be rejected")
+	}
+}
+
+func TestPolicyFromEnvWithoutKey(t *testing.T) {
+	t.Setenv("COSIGN_PUB_KEY_PEM", "")
+	t.Setenv("COSIGN_PUB_KEY", "")
+	t.Setenv("COSIGN_KEYRING", "")
+
+	policy, err := PolicyFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Keys) != 0 || len(policy.Identities) != 0 {
+		t.Fatalf("expected a policy trusting no signer, got %+v", policy)
+	}
+
+	t.Setenv("COSIGN_PUB_KEY", filepath.Join(t.TempDir(), "cosign.pub"))
+	if _, err := PolicyFromEnv(); err == nil {
+		t.Fatal("expected an error for a missing COSIGN_PUB_KEY file")
+	}
+}
+
+func TestLoadTrustedRootMissingFile(t *testing.T) {
+	if _, err := LoadTrustedRoot(filepath.Join(t.TempDir(), "trusted_root.json")); err == nil {
+		t.Fatal("expected an error for a missing trusted root")
+	}
+}
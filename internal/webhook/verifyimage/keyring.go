@@ -0,0 +1,104 @@
+package verifyimage
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/signature"
+)
+
+// Key is one trusted cosign public key.
+type Key struct {
+	// Name identifies the key in results and logs.
+	Name string
+	// PEM is the PEM-encoded public key.
+	PEM string
+	// NotAfter stops the key from being trusted after this time; zero never expires.
+	NotAfter time.Time
+}
+
+// Active reports whether the key is still trusted at now.
+func (k Key) Active(now time.Time) bool {
+	return k.NotAfter.IsZero() || now.Before(k.NotAfter)
+}
+
+// Keyring is a set of trusted keys that can be replaced while verifiers use
+// it, so keys rotate without restarting the manager.
+type Keyring struct {
+	mu   sync.RWMutex
+	keys []Key
+}
+
+// NewKeyring returns a Keyring holding keys.
+func NewKeyring(keys ...Key) *Keyring {
+	k := &Keyring{}
+	k.Set(keys)
+	return k
+}
+
+// Set replaces the keys of the keyring.
+func (k *Keyring) Set(keys []Key) {
+	keys = slices.Clone(keys)
+	slices.SortFunc(keys, func(a, b Key) int { return strings.Compare(a.Name, b.Name) })
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = keys
+}
+
+// Keys returns the keys of the keyring, sorted by name.
+func (k *Keyring) Keys() []Key {
+	if k == nil {
+		return nil
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return slices.Clone(k.keys)
+}
+
+const (
+	keyringPublicKeySuffix = ".pub"
+	keyringNotAfterSuffix  = ".not-after"
+)
+
+// ParseKeyring reads keys from Secret or ConfigMap data. Every "<name>.pub"
+// entry holds a PEM public key, and an optional "<name>.not-after" entry its
+// RFC 3339 expiry. Invalid entries are reported in the error and skipped, so
+// one bad key never blocks a rotation.
+func ParseKeyring(data map[string][]byte) ([]Key, error) {
+	var keys []Key
+	var errs []error
+	for _, entry := range slices.Sorted(maps.Keys(data)) {
+		raw := data[entry]
+		name, ok := strings.CutSuffix(entry, keyringPublicKeySuffix)
+		if !ok {
+			if n, ok := strings.CutSuffix(entry, keyringNotAfterSuffix); ok {
+				if _, found := data[n+keyringPublicKeySuffix]; !found {
+					errs = append(errs, fmt.Errorf("%s: no matching %s entry", entry, n+keyringPublicKeySuffix))
+				}
+			}
+			continue
+		}
+
+		key := Key{Name: name, PEM: strings.TrimSpace(string(raw))}
+		if _, err := signature.LoadPublicKeyRaw([]byte(key.PEM), crypto.SHA256); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry, err))
+			continue
+		}
+		if notAfter, found := data[name+keyringNotAfterSuffix]; found {
+			t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(notAfter)))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name+keyringNotAfterSuffix, err))
+				continue
+			}
+			key.NotAfter = t
+		}
+		keys = append(keys, key)
+	}
+	return keys, errors.Join(errs...)
+}
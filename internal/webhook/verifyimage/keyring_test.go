@@ -0,0 +1,82 @@
+package verifyimage
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func publicKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestParseKeyring(t *testing.T) {
+	keys, err := ParseKeyring(map[string][]byte{
+		"2025.pub":            publicKeyPEM(t),
+		"2025.not-after":      []byte("2026-01-31T00:00:00Z"),
+		"2026.pub":            publicKeyPEM(t),
+		"broken.pub":          []byte("not a key"),
+		"orphan.not-after":    []byte("2026-01-31T00:00:00Z"),
+		"README":              []byte("ignored"),
+		"malformed.pub":       publicKeyPEM(t),
+		"malformed.not-after": []byte("next year"),
+	})
+	if len(keys) != 2 || keys[0].Name != "2025" || keys[1].Name != "2026" {
+		t.Fatalf("expected keys 2025 and 2026, got %+v", keys)
+	}
+	if want := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC); !keys[0].NotAfter.Equal(want) {
+		t.Fatalf("expected 2025 to expire at %s, got %s", want, keys[0].NotAfter)
+	}
+	for _, entry := range []string{"broken.pub", "orphan.not-after", "malformed.not-after"} {
+		if err == nil || !strings.Contains(err.Error(), entry) {
+			t.Fatalf("expected an error about %s, got %v", entry, err)
+		}
+	}
+}
+
+func TestPolicyActiveKeys(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	keyring := NewKeyring(
+		Key{Name: "old", PEM: "old", NotAfter: now.Add(-time.Hour)},
+		Key{Name: "new", PEM: "new"},
+	)
+	policy := Policy{Keys: []Key{{Name: "static", PEM: "static"}}, Keyring: keyring}
+
+	keys := policy.ActiveKeys(now)
+	if len(keys) != 2 || keys[0].Name != "static" || keys[1].Name != "new" {
+		t.Fatalf("expected the static and new keys, got %+v", keys)
+	}
+
+	before := policy.Fingerprint(now)
+	keyring.Set([]Key{{Name: "new", PEM: "new"}, {Name: "next", PEM: "next"}})
+	if policy.Fingerprint(now) == before {
+		t.Fatal("expected rotating the keyring to change the fingerprint")
+	}
+}
+
+func TestVerifyRejectsPolicyWithOnlyExpiredKeys(t *testing.T) {
+	policy := Policy{Name: "rotated", Keys: []Key{{Name: "2025", PEM: "pem", NotAfter: time.Now().Add(-time.Hour)}}}
+
+	_, err := NewCosignVerifier(nil).Verify(context.TODO(), "registry.example.com/app:1.0", policy)
+	if err == nil || !strings.Contains(err.Error(), "trusts no unexpired key") {
+		t.Fatalf("expected the policy to be rejected, got %v", err)
+	}
+	if IsTransient(err) {
+		t.Fatal("expected a policy without trusted keys to be a verdict, not an outage")
+	}
+}
@@ -0,0 +1,100 @@
+package verifyimage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/sigstore/pkg/tuf"
+)
+
+// LoadRekorPublicKeys reads PEM-encoded Rekor public keys from files, so
+// signature bundles are checked against the transparency log without TUF.
+func LoadRekorPublicKeys(paths ...string) (*cosign.TrustedTransparencyLogPubKeys, error) {
+	keys := cosign.NewTrustedTransparencyLogPubKeys()
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read Rekor public key: %w", err)
+		}
+		if err := keys.AddTransparencyLogPubKey(raw, tuf.Active); err != nil {
+			return nil, fmt.Errorf("load Rekor public key %q: %w", path, err)
+		}
+	}
+	return &keys, nil
+}
+
+// Mirror redirects registry traffic for images under Prefix to Replacement,
+// e.g. docker.io to harbor.internal/dockerhub. Signatures must be mirrored
+// next to the images.
+type Mirror struct {
+	Prefix      string
+	Replacement string
+}
+
+// ParseMirrors parses comma-separated "<prefix>=<replacement>" rules. A
+// prefix is a registry host or a repository path.
+func ParseMirrors(spec string) ([]Mirror, error) {
+	var mirrors []Mirror
+	for _, rule := range strings.Split(spec, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		prefix, replacement, ok := strings.Cut(rule, "=")
+		if !ok || prefix == "" || replacement == "" {
+			return nil, fmt.Errorf("invalid mirror rule %q: expected <prefix>=<replacement>", rule)
+		}
+		p, err := normalizeRepoPrefix(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mirror prefix %q: %w", prefix, err)
+		}
+		r, err := normalizeRepoPrefix(replacement)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mirror replacement %q: %w", replacement, err)
+		}
+		mirrors = append(mirrors, Mirror{Prefix: p, Replacement: r})
+	}
+	return mirrors, nil
+}
+
+// normalizeRepoPrefix returns the canonical name of a registry or
+// repository, so docker.io matches the index.docker.io references parse to.
+func normalizeRepoPrefix(s string) (string, error) {
+	if !strings.Contains(s, "/") {
+		reg, err := name.NewRegistry(s)
+		if err != nil {
+			return "", err
+		}
+		return reg.Name(), nil
+	}
+	repo, err := name.NewRepository(s)
+	if err != nil {
+		return "", err
+	}
+	return repo.Name(), nil
+}
+
+// mirror rewrites the repository of ref with the longest matching mirror
+// rule, returning ref unchanged when none matches.
+func (v *CosignVerifier) mirror(ref name.Reference) (name.Reference, error) {
+	repo := ref.Context().Name()
+	var best Mirror
+	for _, m := range v.Mirrors {
+		if (repo == m.Prefix || strings.HasPrefix(repo, m.Prefix+"/")) && len(m.Prefix) > len(best.Prefix) {
+			best = m
+		}
+	}
+	if best.Prefix == "" {
+		return ref, nil
+	}
+	rewritten := best.Replacement + strings.TrimPrefix(repo, best.Prefix)
+	switch r := ref.(type) {
+	case name.Digest:
+		return name.NewDigest(rewritten + "@" + r.DigestStr())
+	default:
+		return name.NewTag(rewritten + ":" + ref.Identifier())
+	}
+}
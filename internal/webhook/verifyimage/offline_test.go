@@ -0,0 +1,75 @@
+package verifyimage
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+func TestMirrorRewritesLongestPrefix(t *testing.T) {
+	const sha = "0000000000000000000000000000000000000000000000000000000000000000"
+	mirrors, err := ParseMirrors("docker.io=harbor.internal/dockerhub, ghcr.io=harbor.internal/ghcr,ghcr.io/shieldx-bot=harbor.internal/shieldx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &CosignVerifier{Mirrors: mirrors}
+	for in, want := range map[string]string{
+		"nginx:1.27":                            "harbor.internal/dockerhub/library/nginx:1.27",
+		"ghcr.io/other/app:v1":                  "harbor.internal/ghcr/other/app:v1",
+		"ghcr.io/shieldx-bot/api@sha256:" + sha: "harbor.internal/shieldx/api@sha256:" + sha,
+		"quay.io/prometheus/node-exporter:v1":   "quay.io/prometheus/node-exporter:v1",
+	} {
+		ref, err := name.ParseReference(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := v.mirror(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name() != want {
+			t.Errorf("mirror(%q) = %q, want %q", in, got.Name(), want)
+		}
+	}
+}
+
+func TestParseMirrorsRejectsMalformedRules(t *testing.T) {
+	for _, spec := range []string{"docker.io", "=harbor.internal", "docker.io=", "docker.io=UPPER/case"} {
+		if _, err := ParseMirrors(spec); err == nil {
+			t.Errorf("ParseMirrors(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestLoadRekorPublicKeys(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "rekor.pub")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadRekorPublicKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys.Keys) != 1 {
+		t.Fatalf("expected 1 Rekor key, got %d", len(keys.Keys))
+	}
+	if _, err := LoadRekorPublicKeys(filepath.Join(t.TempDir(), "missing.pub")); err == nil {
+		t.Fatal("expected an error for a missing key file")
+	}
+}
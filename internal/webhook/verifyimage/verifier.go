@@ -0,0 +1,142 @@
+package verifyimage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Policy describes what an image signature must satisfy.
+type Policy struct {
+	// Name identifies the policy in results and logs.
+	Name string
+	// Keys are the cosign public keys signatures are checked against.
+	Keys []Key
+	// Keyring adds keys that can rotate at runtime; optional.
+	Keyring *Keyring
+	// Identities are the certificate identities trusted for keyless signatures.
+	Identities []Identity
+	// Attestations must all be satisfied by attestations signed by the
+	// signer of the image.
+	Attestations []AttestationRule
+	// IgnoreTlog skips transparency log verification when the Rekor public
+	// keys cannot be loaded.
+	IgnoreTlog bool
+}
+
+// ActiveKeys returns the keys of the policy and its keyring trusted at now.
+func (p Policy) ActiveKeys(now time.Time) []Key {
+	var keys []Key
+	for _, k := range append(slices.Clone(p.Keys), p.Keyring.Keys()...) {
+		if k.Active(now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Fingerprint identifies the policy, the keys it trusts at now and its
+// settings, so cached results are never reused for another policy or after a
+// key is rotated or expires.
+func (p Policy) Fingerprint(now time.Time) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00", p.Name)
+	for _, k := range p.ActiveKeys(now) {
+		fmt.Fprintf(h, "%s\x00%s\x00", k.Name, k.PEM)
+	}
+	for _, id := range p.Identities {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", id.Issuer, id.IssuerRegExp, id.Subject, id.SubjectRegExp)
+	}
+	for _, r := range p.Attestations {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", r.predicateURI(), r.BuilderID, r.SourceRepo)
+	}
+	fmt.Fprintf(h, "%t", p.IgnoreTlog)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// MatchGlob reports whether s matches pattern, where "*" matches any run of
+// characters including "/".
+func MatchGlob(pattern, s string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	return regexp.MustCompile(expr).MatchString(s)
+}
+
+// Result describes a successful verification.
+type Result struct {
+	// Digest is the verified image pinned by digest, repo@sha256:...
+	// Pinning a pod to it guarantees the kubelet pulls what was verified.
+	Digest string
+	// Policy is the name of the policy that was satisfied.
+	Policy string
+	// Key is the name of the key whose signature matched, if any.
+	Key string
+	// Identity is the certificate subject of a matching keyless signature, if any.
+	Identity string
+	// Attestations are the predicate types of the attestations that
+	// satisfied the policy's rules.
+	Attestations []string
+}
+
+// Verifier checks image signatures against a policy.
+type Verifier interface {
+	// Verify resolves imageRef to a digest and verifies it against policy.
+	Verify(ctx context.Context, imageRef string, policy Policy) (Result, error)
+}
+
+// AnyOf returns a Verifier that succeeds as soon as one of verifiers does.
+func AnyOf(verifiers ...Verifier) Verifier {
+	return anyOf(verifiers)
+}
+
+type anyOf []Verifier
+
+func (a anyOf) Verify(ctx context.Context, imageRef string, policy Policy) (Result, error) {
+	errs := make([]error, 0, len(a))
+	for _, v := range a {
+		res, err := v.Verify(ctx, imageRef, policy)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return Result{}, fmt.Errorf("no verifier configured for %q", imageRef)
+	}
+	return Result{}, errors.Join(errs...)
+}
+
+// AllOf returns a Verifier that succeeds only when every one of verifiers
+// does and they all resolved the image to the same digest.
+func AllOf(verifiers ...Verifier) Verifier {
+	return allOf(verifiers)
+}
+
+type allOf []Verifier
+
+func (a allOf) Verify(ctx context.Context, imageRef string, policy Policy) (Result, error) {
+	if len(a) == 0 {
+		return Result{}, fmt.Errorf("no verifier configured for %q", imageRef)
+	}
+	var first Result
+	for i, v := range a {
+		res, err := v.Verify(ctx, imageRef, policy)
+		if err != nil {
+			return Result{}, err
+		}
+		if i == 0 {
+			first = res
+			continue
+		}
+		if res.Digest != first.Digest {
+			return Result{}, fmt.Errorf("verifiers resolved %q to different digests %s and %s",
+				imageRef, first.Digest, res.Digest)
+		}
+	}
+	return first, nil
+}
@@ -0,0 +1,47 @@
+package verifyimage_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage"
+	"github.com/shieldx-bot/shieldx-platform/internal/webhook/verifyimage/fake"
+)
+
+const image = "registry.example.com/app:1.0"
+
+func TestAnyOf(t *testing.T) {
+	rejecting := &fake.Verifier{}
+	accepting := &fake.Verifier{Digests: map[string]string{image: "registry.example.com/app@sha256:aa"}}
+	policy := verifyimage.Policy{Name: "default"}
+
+	res, err := verifyimage.AnyOf(rejecting, accepting).Verify(context.TODO(), image, policy)
+	if err != nil || res.Digest != "registry.example.com/app@sha256:aa" || res.Policy != "default" {
+		t.Fatalf("expected the accepting verifier's result, got %+v, %v", res, err)
+	}
+	if _, err := verifyimage.AnyOf(rejecting, rejecting).Verify(context.TODO(), image, policy); err == nil {
+		t.Fatal("expected an error when every verifier rejects the image")
+	}
+	if _, err := verifyimage.AnyOf().Verify(context.TODO(), image, policy); err == nil {
+		t.Fatal("expected an error without verifiers")
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	first := &fake.Verifier{Digests: map[string]string{image: "registry.example.com/app@sha256:aa"}}
+	second := &fake.Verifier{Digests: map[string]string{image: "registry.example.com/app@sha256:aa"}}
+	moved := &fake.Verifier{Digests: map[string]string{image: "registry.example.com/app@sha256:bb"}}
+	policy := verifyimage.Policy{Name: "default"}
+
+	if _, err := verifyimage.AllOf(first, second).Verify(context.TODO(), image, policy); err != nil {
+		t.Fatalf("expected both verifiers to accept the image, got %v", err)
+	}
+	if _, err := verifyimage.AllOf(first, &fake.Verifier{}).Verify(context.TODO(), image, policy); err == nil {
+		t.Fatal("expected an error when one verifier rejects the image")
+	}
+	_, err := verifyimage.AllOf(first, moved).Verify(context.TODO(), image, policy)
+	if err == nil || !strings.Contains(err.Error(), "different digests") {
+		t.Fatalf("expected a digest mismatch error, got %v", err)
+	}
+}
@@ -2,31 +2,24 @@ package verifyimage
 
 import (
 	"context"
+	"crypto"
+	"errors"
 	"fmt"
-	"io"
-	"log"
+	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// defaultCosignPublicKeyPEM is a built-in fallback public key.
-//
-// Security note:
-//   - Hardcoding a *public* key is acceptable for demos and tightly controlled environments.
-//   - In production, prefer injecting the key via Kubernetes Secret -> env (COSIGN_PUB_KEY_PEM),
-//     so you can rotate the key without rebuilding the controller image.
-const defaultCosignPublicKeyPEM = ` 
------BEGIN PUBLIC KEY-----
- Không khuyến nghị hardcode khóa công khai trong mã nguồn. Vui lòng sử dụng biến môi trường hoặc bí mật Kubernetes để quản lý khóa công khai một cách an toàn.
------END PUBLIC KEY-----
-
-`
-
 func getenv(key, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -34,98 +27,212 @@ func getenv(key, def string) string {
 	return def
 }
 
-func buildCosignCheckOpts(ctx context.Context) (*cosign.CheckOpts, func(), error) {
+// PolicyFromEnv builds the policy configured for the controller. It trusts
+// the key from COSIGN_PUB_KEY_PEM, or from the file named by COSIGN_PUB_KEY,
+// the keyring named by COSIGN_KEYRING and the keyless identity configured with
+// the COSIGN_CERTIFICATE_* variables. With none of them the policy trusts no
+// signer, so images no policy object matches are rejected.
+func PolicyFromEnv() (Policy, error) {
+	policy := Policy{
+		Name:       "default",
+		IgnoreTlog: getenv("COSIGN_IGNORE_TLOG", "false") == "true",
+	}
+
+	id := Identity{
+		Issuer:        os.Getenv("COSIGN_CERTIFICATE_OIDC_ISSUER"),
+		IssuerRegExp:  os.Getenv("COSIGN_CERTIFICATE_OIDC_ISSUER_REGEXP"),
+		Subject:       os.Getenv("COSIGN_CERTIFICATE_IDENTITY"),
+		SubjectRegExp: os.Getenv("COSIGN_CERTIFICATE_IDENTITY_REGEXP"),
+	}
+	if id != (Identity{}) {
+		if err := id.Validate(); err != nil {
+			return Policy{}, fmt.Errorf("invalid COSIGN_CERTIFICATE_* identity: %w", err)
+		}
+		policy.Identities = []Identity{id}
+	}
+	rules, err := ParseAttestationRules(os.Getenv("COSIGN_ATTESTATIONS"))
+	if err != nil {
+		return Policy{}, fmt.Errorf("invalid COSIGN_ATTESTATIONS: %w", err)
+	}
+	policy.Attestations = rules
+
 	// Preferred path: load the PEM content from an env var injected by Kubernetes.
 	// In this repo, the controller Deployment maps:
 	//   env: COSIGN_PUB_KEY_PEM <- secretKeyRef(name=cosign-pub-key, key=cosign.pub)
-	pem := strings.TrimSpace(os.Getenv("COSIGN_PUB_KEY_PEM"))
-	if pem == "" {
-		// Fallback: use the built-in default key.
-		pem = strings.TrimSpace(defaultCosignPublicKeyPEM)
+	if pem := strings.TrimSpace(os.Getenv("COSIGN_PUB_KEY_PEM")); pem != "" {
+		policy.Keys = []Key{{Name: "COSIGN_PUB_KEY_PEM", PEM: pem}}
+		return policy, nil
 	}
-	if pem != "" {
-		f, err := os.CreateTemp("", "cosign-pub-*.pem")
+	if pubKeyPath := os.Getenv("COSIGN_PUB_KEY"); pubKeyPath != "" {
+		raw, err := os.ReadFile(pubKeyPath)
 		if err != nil {
-			return nil, nil, fmt.Errorf("create temp file for cosign public key: %w", err)
-		}
-		cleanup := func() {
-			_ = f.Close()
-			_ = os.Remove(f.Name())
-		}
-		if _, err := io.WriteString(f, pem+"\n"); err != nil {
-			cleanup()
-			return nil, nil, fmt.Errorf("write cosign public key PEM to temp file: %w", err)
-		}
-		if err := f.Close(); err != nil {
-			cleanup()
-			return nil, nil, fmt.Errorf("close cosign public key temp file: %w", err)
+			return Policy{}, fmt.Errorf("load COSIGN_PUB_KEY: %w", err)
 		}
-
-		verifier, err := signature.LoadPublicKey(ctx, f.Name())
-		if err != nil {
-			cleanup()
-			return nil, nil, fmt.Errorf("load cosign public key from COSIGN_PUB_KEY_PEM: %w", err)
-		}
-		return &cosign.CheckOpts{SigVerifier: verifier}, cleanup, nil
+		policy.Keys = []Key{{Name: pubKeyPath, PEM: strings.TrimSpace(string(raw))}}
 	}
+	return policy, nil
+}
 
-	// Backward-compatible fallback for local dev tooling.
-	// If you want to forbid filesystem keys entirely, remove this block.
-	pubKeyPath := getenv("COSIGN_PUB_KEY", "./cosign.pub")
-	verifier, err := signature.LoadPublicKey(ctx, pubKeyPath)
-	if err != nil {
-		return nil, nil, fmt.Errorf("cosign public key not found in env COSIGN_PUB_KEY_PEM and failed to load from path %q: %w", pubKeyPath, err)
-	}
-	return &cosign.CheckOpts{SigVerifier: verifier}, func() {}, nil
+// CosignVerifier verifies cosign signatures made with one of the policy's
+// keys or, for keyless signatures, by one of its certificate identities.
+type CosignVerifier struct {
+	// Cache remembers results per digest and policy; optional.
+	Cache *Cache
+	// Timeout bounds one verification; defaults to 10s.
+	Timeout time.Duration
+	// TrustedRoot holds the Fulcio CAs and Rekor/CT log keys. When nil,
+	// key-based signatures are checked against the Rekor keys from TUF and
+	// the public Sigstore root is fetched for keyless ones.
+	TrustedRoot root.TrustedMaterial
+	// RekorPubKeys replaces the Rekor keys fetched through TUF for key-based signatures.
+	RekorPubKeys *cosign.TrustedTransparencyLogPubKeys
+	// Offline checks transparency log inclusion from the signature bundle
+	// alone, never contacting Rekor.
+	Offline bool
+	// Mirrors redirects registry traffic, e.g. in air-gapped clusters.
+	Mirrors []Mirror
+
+	live liveRoot
 }
 
-func VerifyImageSignature(image string) error {
+var _ Verifier = &CosignVerifier{}
+
+// NewCosignVerifier returns a CosignVerifier sharing cache.
+func NewCosignVerifier(cache *Cache) *CosignVerifier {
+	return &CosignVerifier{Cache: cache}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// Verify implements Verifier.
+func (v *CosignVerifier) Verify(ctx context.Context, image string, policy Policy) (Result, error) {
+	timeout := v.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	img := strings.TrimSpace(image)
 	if img == "" {
-		return fmt.Errorf("empty image")
+		return Result{}, fmt.Errorf("empty image")
 	}
 
 	if strings.HasSuffix(img, ".sig") && strings.Contains(img, ":sha256-") {
-		return fmt.Errorf("image looks like a cosign signature artifact tag (ends with .sig); verify the real image tag/digest instead, e.g. repo:tag or repo@sha256:...")
+		return Result{}, fmt.Errorf("image looks like a cosign signature artifact tag (ends with .sig); verify the real image tag/digest instead, e.g. repo:tag or repo@sha256:...")
+	}
+	// A policy whose keys have all expired is a verdict, not an outage.
+	now := time.Now()
+	if len(policy.ActiveKeys(now)) == 0 && len(policy.Identities) == 0 {
+		return Result{}, fmt.Errorf("verify failed for %q: policy %q trusts no unexpired key or identity", img, policy.Name)
 	}
-	ref, err := name.ParseReference(image)
+	ref, err := name.ParseReference(img)
 	if err != nil {
-		return err
+		return Result{}, err
 	}
-	co, cleanup, err := buildCosignCheckOpts(ctx)
+	fetchRef, err := v.mirror(ref)
 	if err != nil {
-		return err
+		return Result{}, fmt.Errorf("mirror %q: %w", img, err)
+	}
+	digest, err := ociremote.ResolveDigest(fetchRef, ociremote.WithRemoteOptions(remote.WithContext(ctx)))
+	if err != nil {
+		err = fmt.Errorf("resolve digest for %q: %w", img, err)
+		// A registry that answers "not found" has given a verdict; anything else is an outage.
+		var terr *transport.Error
+		if !errors.As(err, &terr) || terr.StatusCode != http.StatusNotFound {
+			err = transientError{err}
+		}
+		return Result{}, err
 	}
-	defer cleanup()
 
-	if rekorPubs, e := cosign.GetRekorPubs(ctx); e == nil {
+	check := func() (Result, error) { return v.verifyDigest(ctx, digest, policy, now) }
+	var res Result
+	if v.Cache != nil {
+		res, err = v.Cache.Do(digest.String()+"|"+policy.Fingerprint(now), check)
+	} else {
+		res, err = check()
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("verify failed for %q: %w", img, err)
+	}
+	// Pods keep pulling from the original repository; mirrors serve the same digest.
+	res.Digest = ref.Context().Digest(digest.DigestStr()).String()
+	return res, nil
+}
+
+// verifyDigest checks the signatures of one digest against every key the
+// policy trusts at now, then against its keyless identities, reporting the
+// first match.
+func (v *CosignVerifier) verifyDigest(ctx context.Context, digest name.Digest, policy Policy, now time.Time) (Result, error) {
+	keys := policy.ActiveKeys(now)
+	errs := make([]error, 0, len(keys)+1)
+	if len(keys) > 0 {
+		co, err := v.keyCheckOpts(ctx, policy)
+		if err != nil {
+			return Result{}, err
+		}
+		for _, key := range keys {
+			verifier, err := signature.LoadPublicKeyRaw([]byte(key.PEM), crypto.SHA256)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("key %q: load public key: %w", key.Name, err))
+				continue
+			}
+			co.SigVerifier = verifier
+			if _, _, err := cosign.VerifyImageSignatures(ctx, digest, co); err != nil {
+				errs = append(errs, fmt.Errorf("key %q: %w", key.Name, classify(err)))
+				continue
+			}
+			atts, err := verifyAttestations(ctx, digest, co, policy.Attestations)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("key %q: %w", key.Name, err))
+				continue
+			}
+			return Result{Digest: digest.String(), Policy: policy.Name, Key: key.Name, Attestations: atts}, nil
+		}
+	}
+
+	if len(policy.Identities) > 0 {
+		res, err := v.verifyKeyless(ctx, digest, policy)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, err)
+	}
+	return Result{}, errors.Join(errs...)
+}
+
+// keyCheckOpts returns the transparency log settings for key-based signatures.
+func (v *CosignVerifier) keyCheckOpts(ctx context.Context, policy Policy) (*cosign.CheckOpts, error) {
+	if v.TrustedRoot != nil {
+		return &cosign.CheckOpts{TrustedMaterial: v.TrustedRoot, Offline: v.Offline}, nil
+	}
+	co := &cosign.CheckOpts{Offline: v.Offline}
+	if v.RekorPubKeys != nil {
+		co.RekorPubKeys = v.RekorPubKeys
+	} else if rekorPubs, e := cosign.GetRekorPubs(ctx); e == nil {
 		co.RekorPubKeys = rekorPubs
 	} else {
-		if getenv("COSIGN_IGNORE_TLOG", "false") == "true" {
+		if policy.IgnoreTlog {
 			co.IgnoreTlog = true
-			log.Printf("warning: cannot load Rekor public keys (%v); COSIGN_IGNORE_TLOG=true so skipping tlog verification", e)
+			logf.FromContext(ctx).Info("Cannot load Rekor public keys; skipping transparency log verification as COSIGN_IGNORE_TLOG=true", "error", e.Error())
 		} else {
-			return fmt.Errorf("cannot load Rekor public keys (needed to verify bundle): %w (set COSIGN_IGNORE_TLOG=true to skip tlog verification)", e)
+			return nil, transientError{fmt.Errorf("cannot load Rekor public keys (needed to verify bundle): %w (set COSIGN_IGNORE_TLOG=true to skip tlog verification)", e)}
 		}
 	}
+	return co, nil
+}
 
-	_, _, err = cosign.VerifyImageSignatures(ctx, ref, co)
-	if err != nil {
-		return fmt.Errorf("verify failed for %q: %w", img, err)
+// classify marks errors that are not a verdict on the signatures, such as a
+// registry or Rekor outage, as transient.
+func classify(err error) error {
+	var (
+		failure       *cosign.VerificationFailure
+		verification  *cosign.VerificationError
+		noMatch       *cosign.ErrNoMatchingSignatures
+		noSignatures  *cosign.ErrNoSignaturesFound
+		noAttestation *cosign.ErrNoMatchingAttestations
+		noCert        *cosign.ErrNoCertificateFoundOnSignature
+	)
+	if err == nil || errors.As(err, &failure) || errors.As(err, &verification) || errors.As(err, &noMatch) ||
+		errors.As(err, &noSignatures) || errors.As(err, &noAttestation) || errors.As(err, &noCert) {
+		return err
 	}
-	return nil
-
+	return transientError{err}
 }
-
-// func main() {
-// 	image := "shieldxbot/backend_example:v1.0.0"
-// 	err := VerifyImageSignature(image)
-// 	if err != nil {
-// 		log.Fatalf("Image signature verification failed: %v", err)
-// 	} else {
-// 		log.Printf("Image signature verification succeeded for %s", image)
-// 	}
-// }